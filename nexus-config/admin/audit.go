@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/krustd/gf-nexus/nexus-config/auth"
+	"github.com/krustd/gf-nexus/nexus-config/common"
+	"github.com/krustd/gf-nexus/nexus-config/storage"
+)
+
+// OperatorHeader 未启用鉴权时标识操作人的请求头
+const OperatorHeader = "X-Operator"
+
+// operator 返回当前请求的操作人：启用鉴权时为登录用户，否则取 X-Operator 请求头
+func (h *Handler) operator(r *ghttp.Request) string {
+	if p := auth.FromContext(r.Context()); p != nil {
+		return p.Username
+	}
+	return r.Header.Get(OperatorHeader)
+}
+
+// publisher 返回发布记录的发布人：启用鉴权时总是登录用户，忽略请求中的 publisher；
+// 未启用鉴权时取请求中的 publisher，为空时取操作人
+func (h *Handler) publisher(r *ghttp.Request, requested string) string {
+	if auth.FromContext(r.Context()) != nil || requested == "" {
+		return h.operator(r)
+	}
+	return requested
+}
+
+// audit 追加审计记录；before / after 为字符串时原样记录，其余类型记录 JSON，nil 记为空。
+// 变更已生效，写入失败只记录日志
+func (h *Handler) audit(r *ghttp.Request, action common.AuditAction, namespace, key string, before, after interface{}) {
+	entry := &common.AuditLog{
+		Operator:  h.operator(r),
+		Action:    action,
+		Namespace: namespace,
+		Key:       key,
+		Before:    auditValue(before),
+		After:     auditValue(after),
+		ClientIP:  r.GetClientIp(),
+	}
+	if err := h.storage.AppendAudit(context.Background(), entry); err != nil {
+		g.Log().Errorf(context.Background(), "append audit log failed: %s %s/%s: %v", action, namespace, key, err)
+	}
+}
+
+func auditValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
+
+// AuditPage 审计记录分页结果
+type AuditPage struct {
+	List     []*common.AuditLog `json:"list"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+// ListAudits 查询审计记录，支持 operator / action / namespace / key / since / until 过滤与分页
+func (h *Handler) ListAudits(r *ghttp.Request) {
+	query := &storage.AuditQuery{
+		Operator:  r.Get("operator").String(),
+		Action:    common.AuditAction(r.Get("action").String()),
+		Namespace: r.Get("namespace").String(),
+		Key:       r.Get("key").String(),
+		Since:     r.Get("since").Time(),
+		Until:     r.Get("until").Time(),
+		Page:      r.Get("page", 1).Int(),
+		PageSize:  r.Get("page_size", 20).Int(),
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 || query.PageSize > 200 {
+		query.PageSize = 20
+	}
+
+	list, total, err := h.storage.ListAudits(context.Background(), query)
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	r.Response.WriteJson(SuccessResp(&AuditPage{
+		List:     list,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}))
+}
@@ -1,6 +1,10 @@
 package admin
 
-import "github.com/krustd/gf-nexus/nexus-config/common"
+import (
+	"time"
+
+	"github.com/krustd/gf-nexus/nexus-config/common"
+)
 
 // CreateNamespaceReq 创建命名空间请求
 type CreateNamespaceReq struct {
@@ -21,6 +25,17 @@ type SaveDraftReq struct {
 type PublishConfigReq struct {
 	Namespace string `json:"namespace" v:"required"`
 	Key       string `json:"key" v:"required"`
+	Publisher string `json:"publisher"` // 仅未启用鉴权时生效，启用时为登录用户
+	Comment   string `json:"comment" v:"length:0,512"`
+}
+
+// RollbackReq 回滚请求
+type RollbackReq struct {
+	Namespace string `json:"namespace" v:"required"`
+	Key       string `json:"key" v:"required"`
+	ReleaseID int64  `json:"release_id" v:"required|min:1"`
+	Publisher string `json:"publisher"` // 仅未启用鉴权时生效，启用时为登录用户
+	Comment   string `json:"comment" v:"length:0,512"`
 }
 
 // GetConfigReq 获取配置请求
@@ -66,3 +81,56 @@ func ErrorResp(code int, msg string) *Response {
 		Msg:  msg,
 	}
 }
+
+// LoginReq 登录请求
+type LoginReq struct {
+	Username string `json:"username" v:"required"`
+	Password string `json:"password" v:"required"`
+}
+
+// LoginResp 登录响应，token 用于 Authorization: Bearer
+type LoginResp struct {
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ChangePasswordReq 修改本人密码请求
+type ChangePasswordReq struct {
+	OldPassword string `json:"old_password" v:"required"`
+	NewPassword string `json:"new_password" v:"required|length:8,128"`
+}
+
+// CreateUserReq 创建用户请求
+type CreateUserReq struct {
+	Username string `json:"username" v:"required|length:1,64"`
+	Password string `json:"password" v:"required|length:8,128"`
+}
+
+// UpdateUserReq 更新用户请求，password 为空表示不修改
+type UpdateUserReq struct {
+	Username string `json:"username" v:"required"`
+	Password string `json:"password" v:"length:0,128"`
+	Disabled bool   `json:"disabled"`
+}
+
+// SaveRoleBindingReq 保存角色绑定请求，namespace 为 * 表示全部命名空间
+type SaveRoleBindingReq struct {
+	Username  string      `json:"username" v:"required"`
+	Namespace string      `json:"namespace" v:"required"`
+	Role      common.Role `json:"role" v:"required|in:viewer,editor,publisher,admin"`
+}
+
+// CreateTokenReq 创建访问令牌请求
+type CreateTokenReq struct {
+	Kind       common.TokenKind `json:"kind" v:"required|in:user,client"`
+	Name       string           `json:"name" v:"required|length:1,128"`
+	Username   string           `json:"username"`   // user 令牌所属用户
+	Namespaces []string         `json:"namespaces"` // client 令牌可访问的命名空间，["*"] 表示全部
+	TTL        int              `json:"ttl"`        // 有效期（秒），0 表示永不过期
+}
+
+// CreateTokenResp 创建访问令牌响应，明文令牌只返回这一次
+type CreateTokenResp struct {
+	Token string              `json:"token"`
+	Info  *common.AccessToken `json:"info"`
+}
@@ -2,9 +2,11 @@ package admin
 
 import (
 	"context"
+	"errors"
 
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/krustd/gf-nexus/nexus-config/auth"
 	"github.com/krustd/gf-nexus/nexus-config/common"
 	"github.com/krustd/gf-nexus/nexus-config/storage"
 )
@@ -17,12 +19,14 @@ type ConfigNotifier interface {
 type Handler struct {
 	storage  storage.Storage
 	notifier ConfigNotifier
+	auth     *auth.Authenticator
 }
 
-func NewHandler(storage storage.Storage, notifier ConfigNotifier) *Handler {
+func NewHandler(storage storage.Storage, notifier ConfigNotifier, authn *auth.Authenticator) *Handler {
 	return &Handler{
 		storage:  storage,
 		notifier: notifier,
+		auth:     authn,
 	}
 }
 
@@ -46,6 +50,7 @@ func (h *Handler) CreateNamespace(r *ghttp.Request) {
 		return
 	}
 
+	h.audit(r, common.AuditNamespaceCreate, ns.ID, "", nil, ns)
 	r.Response.WriteJson(SuccessResp(ns))
 }
 
@@ -55,7 +60,15 @@ func (h *Handler) ListNamespaces(r *ghttp.Request) {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
-	r.Response.WriteJson(SuccessResp(list))
+
+	// 只返回当前用户可查看的命名空间
+	visible := list[:0]
+	for _, ns := range list {
+		if auth.Allowed(r.Context(), ns.ID, common.RoleViewer) {
+			visible = append(visible, ns)
+		}
+	}
+	r.Response.WriteJson(SuccessResp(visible))
 }
 
 func (h *Handler) GetNamespace(r *ghttp.Request) {
@@ -70,10 +83,12 @@ func (h *Handler) GetNamespace(r *ghttp.Request) {
 
 func (h *Handler) DeleteNamespace(r *ghttp.Request) {
 	id := r.Get("id").String()
+	before, _ := h.storage.GetNamespace(context.Background(), id)
 	if err := h.storage.DeleteNamespace(context.Background(), id); err != nil {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
+	h.audit(r, common.AuditNamespaceDelete, id, "", before, nil)
 	r.Response.WriteJson(SuccessResp(nil))
 }
 
@@ -86,11 +101,18 @@ func (h *Handler) SaveDraft(r *ghttp.Request) {
 		return
 	}
 
+	var before string
+	if item, err := h.storage.GetDraft(context.Background(), req.Namespace, req.Key); err == nil {
+		before = item.DraftValue
+	}
+
 	if err := h.storage.SaveDraft(context.Background(), req.Namespace, req.Key, req.Value, req.Format); err != nil {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
 
+	h.audit(r, common.AuditConfigDraft, req.Namespace, req.Key, before, req.Value)
+
 	r.Response.WriteJson(SuccessResp(nil))
 }
 
@@ -115,32 +137,47 @@ func (h *Handler) PublishConfig(r *ghttp.Request) {
 	}
 
 	ctx := context.Background()
+	req.Publisher = h.publisher(r, req.Publisher)
+
+	var before string
+	if item, err := h.storage.GetDraft(ctx, req.Namespace, req.Key); err == nil {
+		before = item.PublishedValue
+	}
 
-	if err := h.storage.PublishConfig(ctx, req.Namespace, req.Key); err != nil {
+	release, err := h.storage.PublishConfig(ctx, req.Namespace, req.Key, req.Publisher, req.Comment)
+	if err != nil {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
 
-	g.Log().Infof(ctx, "config published: %s/%s", req.Namespace, req.Key)
+	h.audit(r, common.AuditConfigPublish, req.Namespace, req.Key, before, release.Value)
+
+	g.Log().Infof(ctx, "config published: %s/%s, release=%d", req.Namespace, req.Key, release.ID)
 
 	// 通知配置变更
-	if h.notifier != nil {
-		// 获取发布后的配置并通知
-		item, err := h.storage.GetPublishedConfig(ctx, req.Namespace, req.Key)
-		if err == nil {
-			version := &common.ConfigVersion{
-				Namespace: item.Namespace,
-				Key:       item.Key,
-				MD5:       item.PublishedMD5,
-				Value:     item.PublishedValue,
-				Format:    string(item.Format),
-			}
-			h.notifier.Notify(ctx, version)
-			g.Log().Infof(ctx, "config change notified: %s/%s", req.Namespace, req.Key)
-		}
-	}
+	h.notifyPublished(ctx, req.Namespace, req.Key)
 
-	r.Response.WriteJson(SuccessResp(nil))
+	r.Response.WriteJson(SuccessResp(release))
+}
+
+// notifyPublished 将当前已发布配置推送给长轮询中的客户端
+func (h *Handler) notifyPublished(ctx context.Context, namespace, key string) {
+	if h.notifier == nil {
+		return
+	}
+	item, err := h.storage.GetPublishedConfig(ctx, namespace, key)
+	if err != nil {
+		return
+	}
+	version := &common.ConfigVersion{
+		Namespace: item.Namespace,
+		Key:       item.Key,
+		MD5:       item.PublishedMD5,
+		Value:     item.PublishedValue,
+		Format:    string(item.Format),
+	}
+	h.notifier.Notify(ctx, version)
+	g.Log().Infof(ctx, "config change notified: %s/%s", namespace, key)
 }
 
 func (h *Handler) GetPublished(r *ghttp.Request) {
@@ -172,14 +209,80 @@ func (h *Handler) DeleteConfig(r *ghttp.Request) {
 	namespace := r.Get("namespace").String()
 	key := r.Get("key").String()
 
+	before, _ := h.storage.GetDraft(context.Background(), namespace, key)
 	if err := h.storage.DeleteConfig(context.Background(), namespace, key); err != nil {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
 
+	h.audit(r, common.AuditConfigDelete, namespace, key, before, nil)
+
 	r.Response.WriteJson(SuccessResp(nil))
 }
 
+// === 发布历史 ===
+
+func (h *Handler) ListReleases(r *ghttp.Request) {
+	namespace := r.Get("namespace").String()
+	key := r.Get("key").String()
+	limit := r.Get("limit").Int()
+
+	list, err := h.storage.ListReleases(context.Background(), namespace, key, limit)
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	r.Response.WriteJson(SuccessResp(list))
+}
+
+func (h *Handler) GetRelease(r *ghttp.Request) {
+	id := r.Get("id").Int64()
+
+	release, err := h.storage.GetRelease(context.Background(), id)
+	if err != nil || !auth.Allowed(r.Context(), release.Namespace, common.RoleViewer) {
+		r.Response.WriteJson(ErrorResp(404, "release not found"))
+		return
+	}
+
+	r.Response.WriteJson(SuccessResp(release))
+}
+
+func (h *Handler) Rollback(r *ghttp.Request) {
+	var req RollbackReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+
+	ctx := context.Background()
+	req.Publisher = h.publisher(r, req.Publisher)
+
+	var before string
+	if item, err := h.storage.GetDraft(ctx, req.Namespace, req.Key); err == nil {
+		before = item.PublishedValue
+	}
+
+	release, err := h.storage.Rollback(ctx, req.Namespace, req.Key, req.ReleaseID, req.Publisher, req.Comment)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			r.Response.WriteJson(ErrorResp(404, "release not found"))
+			return
+		}
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditConfigRollback, req.Namespace, req.Key, before, release.Value)
+
+	g.Log().Infof(ctx, "config rolled back: %s/%s, to release=%d", req.Namespace, req.Key, req.ReleaseID)
+
+	// 通知客户端立即切换到回滚后的版本
+	h.notifyPublished(ctx, req.Namespace, req.Key)
+
+	r.Response.WriteJson(SuccessResp(release))
+}
+
 // === 灰度规则管理 ===
 
 func (h *Handler) SaveGrayRule(r *ghttp.Request) {
@@ -196,11 +299,14 @@ func (h *Handler) SaveGrayRule(r *ghttp.Request) {
 		Enabled:    req.Enabled,
 	}
 
+	before, _ := h.storage.GetGrayRule(context.Background(), req.Namespace, req.Key)
 	if err := h.storage.SaveGrayRule(context.Background(), rule); err != nil {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
 
+	h.audit(r, common.AuditGraySave, req.Namespace, req.Key, before, rule)
+
 	r.Response.WriteJson(SuccessResp(rule))
 }
 
@@ -221,11 +327,14 @@ func (h *Handler) DeleteGrayRule(r *ghttp.Request) {
 	namespace := r.Get("namespace").String()
 	key := r.Get("key").String()
 
+	before, _ := h.storage.GetGrayRule(context.Background(), namespace, key)
 	if err := h.storage.DeleteGrayRule(context.Background(), namespace, key); err != nil {
 		r.Response.WriteJson(ErrorResp(500, err.Error()))
 		return
 	}
 
+	h.audit(r, common.AuditGrayDelete, namespace, key, before, nil)
+
 	r.Response.WriteJson(SuccessResp(nil))
 }
 
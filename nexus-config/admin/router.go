@@ -4,12 +4,19 @@ import (
 	"os"
 
 	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/krustd/gf-nexus/nexus-config/auth"
+	"github.com/krustd/gf-nexus/nexus-config/common"
 	"github.com/krustd/gf-nexus/nexus-config/storage"
 )
 
-// SetupRouter 设置 Admin API 路由
-func SetupRouter(s *ghttp.Server, store storage.Storage, notifier ConfigNotifier) {
-	handler := NewHandler(store, notifier)
+// SetupRouter 设置 Admin API 路由；authn 为 nil 时不鉴权
+func SetupRouter(s *ghttp.Server, store storage.Storage, notifier ConfigNotifier, authn *auth.Authenticator) {
+	if authn == nil {
+		authn = auth.NewAuthenticator(store, common.AuthConfig{})
+	}
+	handler := NewHandler(store, notifier, authn)
+	require := authn.Require
+	ns := auth.Param("namespace")
 
 	// 检测静态文件路径（支持从不同目录运行）
 	assetsPath := "web/dist/assets"
@@ -23,30 +30,62 @@ func SetupRouter(s *ghttp.Server, store storage.Storage, notifier ConfigNotifier
 
 	// API 路由组
 	s.Group("/api/v1", func(group *ghttp.RouterGroup) {
+		group.Middleware(authn.Middleware)
+
+		// 登录与当前用户
+		group.Group("/auth", func(g *ghttp.RouterGroup) {
+			g.POST("/login", handler.Login)
+			g.POST("/logout", handler.Logout)
+			g.GET("/me", handler.Me)
+			g.POST("/password", handler.ChangePassword)
+		})
+
 		// Namespace 管理
 		group.Group("/namespaces", func(g *ghttp.RouterGroup) {
-			g.POST("/", handler.CreateNamespace)
+			g.POST("/", require(common.RoleAdmin, auth.Global, handler.CreateNamespace))
 			g.GET("/", handler.ListNamespaces)
-			g.GET("/:id", handler.GetNamespace)
-			g.DELETE("/:id", handler.DeleteNamespace)
+			g.GET("/:id", require(common.RoleViewer, auth.Param("id"), handler.GetNamespace))
+			g.DELETE("/:id", require(common.RoleAdmin, auth.Param("id"), handler.DeleteNamespace))
 		})
 
 		// 配置管理
 		group.Group("/configs", func(g *ghttp.RouterGroup) {
-			g.POST("/draft", handler.SaveDraft)
-			g.GET("/draft", handler.GetDraft)
-			g.POST("/publish", handler.PublishConfig)
-			g.GET("/published", handler.GetPublished)
-			g.GET("/list", handler.ListConfigs)
-			g.DELETE("/", handler.DeleteConfig)
+			g.POST("/draft", require(common.RoleEditor, ns, handler.SaveDraft))
+			g.GET("/draft", require(common.RoleViewer, ns, handler.GetDraft))
+			g.POST("/publish", require(common.RolePublisher, ns, handler.PublishConfig))
+			g.GET("/published", require(common.RoleViewer, ns, handler.GetPublished))
+			g.GET("/list", require(common.RoleViewer, ns, handler.ListConfigs))
+			g.DELETE("/", require(common.RolePublisher, ns, handler.DeleteConfig))
+			g.GET("/releases", require(common.RoleViewer, ns, handler.ListReleases))
+			g.GET("/releases/:id", handler.GetRelease)
+			g.POST("/rollback", require(common.RolePublisher, ns, handler.Rollback))
 		})
 
 		// 灰度规则管理
 		group.Group("/gray", func(g *ghttp.RouterGroup) {
-			g.POST("/", handler.SaveGrayRule)
-			g.GET("/", handler.GetGrayRule)
-			g.DELETE("/", handler.DeleteGrayRule)
-			g.GET("/list", handler.ListGrayRules)
+			g.POST("/", require(common.RolePublisher, ns, handler.SaveGrayRule))
+			g.GET("/", require(common.RoleViewer, ns, handler.GetGrayRule))
+			g.DELETE("/", require(common.RolePublisher, ns, handler.DeleteGrayRule))
+			g.GET("/list", require(common.RoleViewer, ns, handler.ListGrayRules))
+		})
+
+		// 审计日志（未指定命名空间时需要全局管理员）
+		group.GET("/audit", require(common.RoleAdmin, ns, handler.ListAudits))
+
+		// 用户、角色与访问令牌管理（全局管理员）
+		group.Group("/users", func(g *ghttp.RouterGroup) {
+			g.GET("/", require(common.RoleAdmin, auth.Global, handler.ListUsers))
+			g.POST("/", require(common.RoleAdmin, auth.Global, handler.CreateUser))
+			g.PUT("/", require(common.RoleAdmin, auth.Global, handler.UpdateUser))
+			g.DELETE("/", require(common.RoleAdmin, auth.Global, handler.DeleteUser))
+			g.GET("/roles", require(common.RoleAdmin, auth.Global, handler.ListRoleBindings))
+			g.POST("/roles", require(common.RoleAdmin, auth.Global, handler.SaveRoleBinding))
+			g.DELETE("/roles", require(common.RoleAdmin, auth.Global, handler.DeleteRoleBinding))
+		})
+		group.Group("/tokens", func(g *ghttp.RouterGroup) {
+			g.GET("/", require(common.RoleAdmin, auth.Global, handler.ListTokens))
+			g.POST("/", require(common.RoleAdmin, auth.Global, handler.CreateToken))
+			g.DELETE("/:id", require(common.RoleAdmin, auth.Global, handler.DeleteToken))
 		})
 	})
 
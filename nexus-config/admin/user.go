@@ -0,0 +1,275 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/krustd/gf-nexus/nexus-config/auth"
+	"github.com/krustd/gf-nexus/nexus-config/common"
+)
+
+// === 登录 ===
+
+func (h *Handler) Login(r *ghttp.Request) {
+	var req LoginReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+
+	if !h.auth.Enabled() {
+		r.Response.WriteJson(ErrorResp(400, "auth not enabled"))
+		return
+	}
+
+	token, info, err := h.auth.Login(context.Background(), req.Username, req.Password)
+	if err != nil {
+		r.Response.Status = 401
+		r.Response.WriteJson(ErrorResp(401, err.Error()))
+		return
+	}
+
+	r.Response.WriteJson(SuccessResp(&LoginResp{Token: token, ExpiresAt: info.ExpiresAt}))
+}
+
+func (h *Handler) Logout(r *ghttp.Request) {
+	if p := auth.FromContext(r.Context()); p != nil {
+		if err := h.storage.DeleteToken(context.Background(), p.TokenID); err != nil {
+			r.Response.WriteJson(ErrorResp(500, err.Error()))
+			return
+		}
+	}
+	r.Response.WriteJson(SuccessResp(nil))
+}
+
+// Me 返回当前用户及其角色绑定
+func (h *Handler) Me(r *ghttp.Request) {
+	p := auth.FromContext(r.Context())
+	if p == nil {
+		r.Response.WriteJson(SuccessResp(map[string]interface{}{"auth_enabled": false}))
+		return
+	}
+	r.Response.WriteJson(SuccessResp(map[string]interface{}{
+		"auth_enabled": true,
+		"username":     p.Username,
+		"roles":        p.Bindings,
+	}))
+}
+
+func (h *Handler) ChangePassword(r *ghttp.Request) {
+	var req ChangePasswordReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+	p := auth.FromContext(r.Context())
+	if p == nil {
+		r.Response.WriteJson(ErrorResp(400, "auth not enabled"))
+		return
+	}
+
+	ctx := context.Background()
+	user, err := h.storage.GetUser(ctx, p.Username)
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.OldPassword) {
+		r.Response.WriteJson(ErrorResp(400, "old password mismatch"))
+		return
+	}
+	if user.PasswordHash, err = auth.HashPassword(req.NewPassword); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+	if err := h.storage.UpdateUser(ctx, user); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditUserUpdate, "", user.Username, nil, nil)
+	r.Response.WriteJson(SuccessResp(nil))
+}
+
+// === 用户管理 ===
+
+func (h *Handler) ListUsers(r *ghttp.Request) {
+	list, err := h.storage.ListUsers(context.Background())
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+	r.Response.WriteJson(SuccessResp(list))
+}
+
+func (h *Handler) CreateUser(r *ghttp.Request) {
+	var req CreateUserReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+	user := &common.User{Username: req.Username, PasswordHash: hash}
+	if err := h.storage.CreateUser(context.Background(), user); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditUserCreate, "", user.Username, nil, user)
+	r.Response.WriteJson(SuccessResp(user))
+}
+
+func (h *Handler) UpdateUser(r *ghttp.Request) {
+	var req UpdateUserReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+
+	ctx := context.Background()
+	user, err := h.storage.GetUser(ctx, req.Username)
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(404, "user not found"))
+		return
+	}
+	before := *user
+
+	user.Disabled = req.Disabled
+	if req.Password != "" {
+		if user.PasswordHash, err = auth.HashPassword(req.Password); err != nil {
+			r.Response.WriteJson(ErrorResp(500, err.Error()))
+			return
+		}
+	}
+	if err := h.storage.UpdateUser(ctx, user); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditUserUpdate, "", user.Username, &before, user)
+	r.Response.WriteJson(SuccessResp(user))
+}
+
+func (h *Handler) DeleteUser(r *ghttp.Request) {
+	username := r.Get("username").String()
+
+	before, _ := h.storage.GetUser(context.Background(), username)
+	if err := h.storage.DeleteUser(context.Background(), username); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditUserDelete, "", username, before, nil)
+	r.Response.WriteJson(SuccessResp(nil))
+}
+
+func (h *Handler) ListRoleBindings(r *ghttp.Request) {
+	list, err := h.storage.ListRoleBindings(context.Background(), r.Get("username").String())
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+	r.Response.WriteJson(SuccessResp(list))
+}
+
+func (h *Handler) SaveRoleBinding(r *ghttp.Request) {
+	var req SaveRoleBindingReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+
+	ctx := context.Background()
+	if _, err := h.storage.GetUser(ctx, req.Username); err != nil {
+		r.Response.WriteJson(ErrorResp(404, "user not found"))
+		return
+	}
+
+	binding := &common.RoleBinding{
+		Username:  req.Username,
+		Namespace: req.Namespace,
+		Role:      req.Role,
+	}
+	if err := h.storage.SaveRoleBinding(ctx, binding); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditRoleBind, req.Namespace, req.Username, nil, binding)
+	r.Response.WriteJson(SuccessResp(binding))
+}
+
+func (h *Handler) DeleteRoleBinding(r *ghttp.Request) {
+	username := r.Get("username").String()
+	namespace := r.Get("namespace").String()
+
+	if err := h.storage.DeleteRoleBinding(context.Background(), username, namespace); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditRoleUnbind, namespace, username, nil, nil)
+	r.Response.WriteJson(SuccessResp(nil))
+}
+
+// === 访问令牌 ===
+
+func (h *Handler) ListTokens(r *ghttp.Request) {
+	kind := common.TokenKind(r.Get("kind", string(common.TokenKindClient)).String())
+
+	list, err := h.storage.ListTokens(context.Background(), kind, r.Get("username").String())
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+	r.Response.WriteJson(SuccessResp(list))
+}
+
+func (h *Handler) CreateToken(r *ghttp.Request) {
+	var req CreateTokenReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.WriteJson(ErrorResp(400, err.Error()))
+		return
+	}
+
+	ctx := context.Background()
+	token := &common.AccessToken{Kind: req.Kind, Name: req.Name}
+	switch req.Kind {
+	case common.TokenKindUser:
+		if _, err := h.storage.GetUser(ctx, req.Username); err != nil {
+			r.Response.WriteJson(ErrorResp(404, "user not found"))
+			return
+		}
+		token.Username = req.Username
+	case common.TokenKindClient:
+		if len(req.Namespaces) == 0 {
+			r.Response.WriteJson(ErrorResp(400, "namespaces required for client token"))
+			return
+		}
+		token.Namespaces = strings.Join(req.Namespaces, ",")
+	}
+
+	plain, info, err := h.auth.IssueToken(ctx, token, time.Duration(req.TTL)*time.Second)
+	if err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditTokenCreate, "", info.Name, nil, info)
+	r.Response.WriteJson(SuccessResp(&CreateTokenResp{Token: plain, Info: info}))
+}
+
+func (h *Handler) DeleteToken(r *ghttp.Request) {
+	id := r.Get("id").Int64()
+
+	if err := h.storage.DeleteToken(context.Background(), id); err != nil {
+		r.Response.WriteJson(ErrorResp(500, err.Error()))
+		return
+	}
+
+	h.audit(r, common.AuditTokenDelete, "", r.Get("id").String(), nil, nil)
+	r.Response.WriteJson(SuccessResp(nil))
+}
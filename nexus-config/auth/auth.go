@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/krustd/gf-nexus/nexus-config/common"
+	"github.com/krustd/gf-nexus/nexus-config/storage"
+)
+
+// ErrInvalidCredentials 用户名或密码错误，或用户已禁用
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+type principalKey struct{}
+
+// Principal 已认证的 Admin API 用户
+type Principal struct {
+	Username string
+	TokenID  int64
+	Bindings map[string]common.Role // 命名空间（* 为全部）→ 角色
+}
+
+// Role 返回用户在 namespace 上的有效角色（取该命名空间与 * 绑定中较高者），无权限时为空
+func (p *Principal) Role(namespace string) common.Role {
+	role := p.Bindings[common.AllNamespaces]
+	if r, ok := p.Bindings[namespace]; ok && r.Level() > role.Level() {
+		role = r
+	}
+	return role
+}
+
+// Can 判断用户在 namespace 上是否至少拥有 role，namespace 为空表示全局权限
+func (p *Principal) Can(namespace string, role common.Role) bool {
+	if namespace == "" {
+		namespace = common.AllNamespaces
+	}
+	return p.Role(namespace).Level() >= role.Level()
+}
+
+// FromContext 返回请求关联的用户，未启用鉴权时为 nil
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// Allowed 判断当前请求在 namespace 上是否至少拥有 role；未启用鉴权时总是允许
+func Allowed(ctx context.Context, namespace string, role common.Role) bool {
+	p := FromContext(ctx)
+	return p == nil || p.Can(namespace, role)
+}
+
+// Authenticator Admin API 鉴权与客户端访问令牌校验
+type Authenticator struct {
+	store storage.Storage
+	cfg   common.AuthConfig
+}
+
+func NewAuthenticator(store storage.Storage, cfg common.AuthConfig) *Authenticator {
+	if cfg.AdminUser == "" {
+		cfg.AdminUser = "admin"
+	}
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = 86400
+	}
+	return &Authenticator{store: store, cfg: cfg}
+}
+
+// Enabled Admin API 是否需要登录
+func (a *Authenticator) Enabled() bool {
+	return a != nil && a.cfg.Enabled
+}
+
+// Bootstrap 启用鉴权且尚无任何用户时创建全局管理员
+func (a *Authenticator) Bootstrap(ctx context.Context) error {
+	if !a.Enabled() {
+		return nil
+	}
+	users, err := a.store.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return nil
+	}
+
+	password := a.cfg.AdminPassword
+	if password == "" {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		password = hex.EncodeToString(buf)
+		g.Log().Warningf(ctx, "auth: created user %s with generated password %s, change it after login", a.cfg.AdminUser, password)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := a.store.CreateUser(ctx, &common.User{Username: a.cfg.AdminUser, PasswordHash: hash}); err != nil {
+		return err
+	}
+	return a.store.SaveRoleBinding(ctx, &common.RoleBinding{
+		Username:  a.cfg.AdminUser,
+		Namespace: common.AllNamespaces,
+		Role:      common.RoleAdmin,
+	})
+}
+
+// Login 校验用户名密码并签发登录令牌
+func (a *Authenticator) Login(ctx context.Context, username, password string) (string, *common.AccessToken, error) {
+	user, err := a.store.GetUser(ctx, username)
+	if err != nil || user.Disabled || !CheckPassword(user.PasswordHash, password) {
+		return "", nil, ErrInvalidCredentials
+	}
+	return a.IssueToken(ctx, &common.AccessToken{
+		Kind:     common.TokenKindUser,
+		Name:     "login",
+		Username: username,
+	}, time.Duration(a.cfg.TokenTTL)*time.Second)
+}
+
+// IssueToken 创建访问令牌，ttl <= 0 表示永不过期；明文令牌只在此处返回
+func (a *Authenticator) IssueToken(ctx context.Context, token *common.AccessToken, ttl time.Duration) (string, *common.AccessToken, error) {
+	plain, hash, err := NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+	token.TokenHash = hash
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := a.store.CreateToken(ctx, token); err != nil {
+		return "", nil, err
+	}
+	return plain, token, nil
+}
+
+// lookup 按明文令牌查找指定类型的有效令牌
+func (a *Authenticator) lookup(ctx context.Context, plain string, kind common.TokenKind) (*common.AccessToken, bool) {
+	if plain == "" {
+		return nil, false
+	}
+	token, err := a.store.GetTokenByHash(ctx, HashToken(plain))
+	if err != nil || token.Kind != kind {
+		return nil, false
+	}
+	if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+		return nil, false
+	}
+	return token, true
+}
+
+// authenticate 解析 Admin API 请求携带的令牌，返回对应用户
+func (a *Authenticator) authenticate(r *ghttp.Request) (*Principal, bool) {
+	ctx := r.Context()
+	token, ok := a.lookup(ctx, bearerToken(r), common.TokenKindUser)
+	if !ok {
+		return nil, false
+	}
+	user, err := a.store.GetUser(ctx, token.Username)
+	if err != nil || user.Disabled {
+		return nil, false
+	}
+	bindings, err := a.store.ListRoleBindings(ctx, user.Username)
+	if err != nil {
+		return nil, false
+	}
+	p := &Principal{
+		Username: user.Username,
+		TokenID:  token.ID,
+		Bindings: make(map[string]common.Role, len(bindings)),
+	}
+	for _, b := range bindings {
+		p.Bindings[b.Namespace] = b.Role
+	}
+	return p, true
+}
+
+// Middleware Admin API 认证中间件，挂载在 /api/v1 分组；登录接口除外
+func (a *Authenticator) Middleware(r *ghttp.Request) {
+	if !a.Enabled() || r.URL.Path == "/api/v1/auth/login" {
+		r.Middleware.Next()
+		return
+	}
+	p, ok := a.authenticate(r)
+	if !ok {
+		deny(r, 401, "unauthorized")
+		return
+	}
+	r.SetCtx(context.WithValue(r.Context(), principalKey{}, p))
+	r.Middleware.Next()
+}
+
+// NamespaceFunc 从请求中取得授权检查的目标命名空间，返回空表示需要全局权限
+type NamespaceFunc func(r *ghttp.Request) string
+
+// Param 从请求参数（query / body / 路由参数）name 中取命名空间
+func Param(name string) NamespaceFunc {
+	return func(r *ghttp.Request) string {
+		return r.Get(name).String()
+	}
+}
+
+// Global 需要全局（* 上的）权限
+func Global(*ghttp.Request) string {
+	return ""
+}
+
+// Require 要求当前用户在目标命名空间上至少拥有 role；ns 为 nil 时只要求已登录
+func (a *Authenticator) Require(role common.Role, ns NamespaceFunc, handler ghttp.HandlerFunc) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		if ns != nil && !Allowed(r.Context(), ns(r), role) {
+			deny(r, 403, "forbidden")
+			return
+		}
+		handler(r)
+	}
+}
+
+// ClientMiddleware 配置分发服务的客户端令牌校验：令牌须为 client 类型且允许访问请求的命名空间
+func (a *Authenticator) ClientMiddleware(r *ghttp.Request) {
+	if a == nil || !a.cfg.ClientToken {
+		r.Middleware.Next()
+		return
+	}
+	token, ok := a.lookup(r.Context(), bearerToken(r), common.TokenKindClient)
+	if !ok {
+		r.Response.Status = 401
+		r.Response.WriteJson(map[string]interface{}{"error": "invalid access token"})
+		return
+	}
+	for _, namespace := range requestNamespaces(r) {
+		if !namespaceAllowed(token.Namespaces, namespace) {
+			r.Response.Status = 403
+			r.Response.WriteJson(map[string]interface{}{"error": "namespace not allowed"})
+			return
+		}
+	}
+	r.Middleware.Next()
+}
+
+// requestNamespaces 返回配置分发请求涉及的命名空间：单个配置取 namespace，批量长轮询取 keys[].namespace
+func requestNamespaces(r *ghttp.Request) []string {
+	keys := r.Get("keys").Maps()
+	if len(keys) == 0 {
+		return []string{r.Get("namespace").String()}
+	}
+	namespaces := make([]string, 0, len(keys))
+	for _, k := range keys {
+		namespace, _ := k["namespace"].(string)
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+func namespaceAllowed(allowed, namespace string) bool {
+	for _, ns := range strings.Split(allowed, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == common.AllNamespaces || (ns != "" && ns == namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(r *ghttp.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+func deny(r *ghttp.Request, status int, msg string) {
+	r.Response.Status = status
+	r.Response.WriteJson(map[string]interface{}{"code": status, "msg": msg})
+}
@@ -13,17 +13,19 @@ type ServerConfig struct {
 	Database DatabaseConfig `json:"database"`
 	Admin    AdminConfig    `json:"admin"`
 	Server   HttpConfig     `json:"server"`
+	Auth     AuthConfig     `json:"auth"`
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Type     string `json:"type"`     // sqlite, mysql
+	Type     string `json:"type"`     // sqlite, mysql, postgres
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	Username string `json:"username"`
 	Password string `json:"password"`
 	Database string `json:"database"`
 	FilePath string `json:"file_path"` // for sqlite
+	SSLMode  string `json:"ssl_mode"`  // for postgres，默认 disable
 }
 
 // AdminConfig Admin API 配置
@@ -31,6 +33,15 @@ type AdminConfig struct {
 	Addr string `json:"addr"`
 }
 
+// AuthConfig Admin API 鉴权与客户端访问令牌
+type AuthConfig struct {
+	Enabled       bool   `json:"enabled"`        // Admin API 需要登录，按命名空间角色授权
+	AdminUser     string `json:"admin_user"`     // 无任何用户时自动创建的管理员，默认 admin
+	AdminPassword string `json:"admin_password"` // 为空时随机生成并打印到日志
+	TokenTTL      int    `json:"token_ttl"`      // 登录令牌有效期（秒），默认 86400
+	ClientToken   bool   `json:"client_token"`   // 配置分发服务要求客户端访问令牌
+}
+
 // HttpConfig HTTP 服务配置
 type HttpConfig struct {
 	Addr string `json:"addr"`
@@ -44,6 +55,8 @@ type ClientConfig struct {
 	ClientID    string `json:"client_id"`     // 客户端唯一标识，用于灰度
 	PollTimeout int    `json:"poll_timeout"`  // 长轮询超时时间（秒）
 	RetryDelay  int    `json:"retry_delay"`   // 重试延迟（秒）
+	AccessToken string `json:"access_token"`  // 配置分发服务的客户端访问令牌
+	SnapshotDir string `json:"snapshot_dir"`  // 本地快照目录，为空时不保存快照
 }
 
 // LoadServerConfig 加载服务端配置
@@ -0,0 +1,162 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeType 配置项变更类型
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// FieldChange 单个配置项（扁平路径）的变更
+type FieldChange struct {
+	Path     string      `json:"path"`
+	Type     ChangeType  `json:"type"`
+	OldValue interface{} `json:"old_value,omitempty"`
+	NewValue interface{} `json:"new_value,omitempty"`
+}
+
+// ChangeSet 两个配置版本之间的字段级差异，Changes 按路径排序
+type ChangeSet struct {
+	Namespace string        `json:"namespace"`
+	Key       string        `json:"key"`
+	OldMD5    string        `json:"old_md5"`
+	NewMD5    string        `json:"new_md5"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// Changed 判断 path 本身或其下任一子路径是否发生变更，如 Changed("server") 覆盖 server.port
+func (cs *ChangeSet) Changed(path string) bool {
+	for _, c := range cs.Changes {
+		if c.Path == path || strings.HasPrefix(c.Path, path+".") || strings.HasPrefix(c.Path, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Paths 返回指定类型的变更路径
+func (cs *ChangeSet) Paths(changeType ChangeType) []string {
+	var paths []string
+	for _, c := range cs.Changes {
+		if c.Type == changeType {
+			paths = append(paths, c.Path)
+		}
+	}
+	return paths
+}
+
+// Flatten 将配置内容解析为扁平的 路径 → 值：嵌套对象以 . 连接，数组元素为 [i]，如 server.port、routes[0].path。
+// properties 格式的键原样作为路径
+func Flatten(content string, format ConfigFormat) (map[string]interface{}, error) {
+	var data interface{}
+	switch format {
+	case FormatTOML, FormatProperties:
+		m := make(map[string]interface{})
+		if err := ParseConfig(content, format, &m); err != nil {
+			return nil, err
+		}
+		data = m
+	default:
+		if err := ParseConfig(content, format, &data); err != nil {
+			return nil, err
+		}
+	}
+
+	flat := make(map[string]interface{})
+	flatten("", data, flat)
+	return flat, nil
+}
+
+func flatten(prefix string, value interface{}, out map[string]interface{}) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for key, child := range v {
+			flatten(join(key), child, out)
+		}
+	case map[interface{}]interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for key, child := range v {
+			flatten(join(fmt.Sprint(key)), child, out)
+		}
+	case []interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for i, child := range v {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	case []map[string]interface{}: // TOML 表数组
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for i, child := range v {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = v
+		}
+	}
+}
+
+// DiffVersions 计算两个配置版本的字段级差异，oldVersion 为 nil 时所有配置项均为新增
+func DiffVersions(oldVersion, newVersion *ConfigVersion) (*ChangeSet, error) {
+	cs := &ChangeSet{
+		Namespace: newVersion.Namespace,
+		Key:       newVersion.Key,
+		NewMD5:    newVersion.MD5,
+	}
+
+	newFlat, err := Flatten(newVersion.Value, ConfigFormat(newVersion.Format))
+	if err != nil {
+		return nil, fmt.Errorf("parse new version %s: %w", newVersion.MD5, err)
+	}
+	oldFlat := make(map[string]interface{})
+	if oldVersion != nil {
+		cs.OldMD5 = oldVersion.MD5
+		if oldFlat, err = Flatten(oldVersion.Value, ConfigFormat(oldVersion.Format)); err != nil {
+			return nil, fmt.Errorf("parse old version %s: %w", oldVersion.MD5, err)
+		}
+	}
+
+	for path, newValue := range newFlat {
+		oldValue, ok := oldFlat[path]
+		switch {
+		case !ok:
+			cs.Changes = append(cs.Changes, FieldChange{Path: path, Type: ChangeAdded, NewValue: newValue})
+		case !reflect.DeepEqual(oldValue, newValue):
+			cs.Changes = append(cs.Changes, FieldChange{Path: path, Type: ChangeModified, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for path, oldValue := range oldFlat {
+		if _, ok := newFlat[path]; !ok {
+			cs.Changes = append(cs.Changes, FieldChange{Path: path, Type: ChangeRemoved, OldValue: oldValue})
+		}
+	}
+
+	sort.Slice(cs.Changes, func(i, j int) bool {
+		return cs.Changes[i].Path < cs.Changes[j].Path
+	})
+	return cs, nil
+}
@@ -46,6 +46,156 @@ func (ConfigItem) TableName() string {
 	return "config_item"
 }
 
+// ReleaseType 发布记录类型
+type ReleaseType string
+
+const (
+	ReleaseTypePublish  ReleaseType = "publish"
+	ReleaseTypeRollback ReleaseType = "rollback"
+)
+
+// ConfigRelease 发布记录，每次发布 / 回滚追加一条，不修改
+type ConfigRelease struct {
+	ID           int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	Namespace    string       `json:"namespace" gorm:"size:64;not null;index:idx_release_ns_key"`
+	Key          string       `json:"key" gorm:"size:128;not null;index:idx_release_ns_key"`
+	Format       ConfigFormat `json:"format" gorm:"size:20"`
+	Value        string       `json:"value" gorm:"type:text"`
+	MD5          string       `json:"md5" gorm:"size:32"`
+	Type         ReleaseType  `json:"type" gorm:"size:20;default:publish"`
+	RollbackFrom int64        `json:"rollback_from,omitempty"` // 回滚时指向被恢复的发布记录
+	Publisher    string       `json:"publisher" gorm:"size:64"`
+	Comment      string       `json:"comment" gorm:"size:512"`
+	CreatedAt    time.Time    `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ConfigRelease) TableName() string {
+	return "config_release"
+}
+
+// AuditAction 审计操作类型
+type AuditAction string
+
+const (
+	AuditNamespaceCreate AuditAction = "namespace.create"
+	AuditNamespaceDelete AuditAction = "namespace.delete"
+	AuditConfigDraft     AuditAction = "config.draft"
+	AuditConfigPublish   AuditAction = "config.publish"
+	AuditConfigRollback  AuditAction = "config.rollback"
+	AuditConfigDelete    AuditAction = "config.delete"
+	AuditGraySave        AuditAction = "gray.save"
+	AuditGrayDelete      AuditAction = "gray.delete"
+	AuditUserCreate      AuditAction = "user.create"
+	AuditUserUpdate      AuditAction = "user.update"
+	AuditUserDelete      AuditAction = "user.delete"
+	AuditRoleBind        AuditAction = "role.bind"
+	AuditRoleUnbind      AuditAction = "role.unbind"
+	AuditTokenCreate     AuditAction = "token.create"
+	AuditTokenDelete     AuditAction = "token.delete"
+)
+
+// AuditLog 管理操作审计记录，只追加不修改
+type AuditLog struct {
+	ID        int64       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Operator  string      `json:"operator" gorm:"size:64;index"`
+	Action    AuditAction `json:"action" gorm:"size:32;index"`
+	Namespace string      `json:"namespace" gorm:"size:64;index:idx_audit_ns_key"`
+	Key       string      `json:"key" gorm:"size:128;index:idx_audit_ns_key"`
+	Before    string      `json:"before" gorm:"type:text"` // 变更前的值，新建时为空
+	After     string      `json:"after" gorm:"type:text"`  // 变更后的值，删除时为空
+	ClientIP  string      `json:"client_ip" gorm:"size:64"`
+	CreatedAt time.Time   `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// Role Admin API 角色，权限依次递增
+type Role string
+
+const (
+	RoleViewer    Role = "viewer"    // 查看配置、草稿、发布历史、灰度规则
+	RoleEditor    Role = "editor"    // + 保存草稿
+	RolePublisher Role = "publisher" // + 发布、回滚、删除配置、灰度规则
+	RoleAdmin     Role = "admin"     // + 删除命名空间、查看审计；绑定在 * 上时可管理命名空间、用户与令牌
+)
+
+// Level 返回角色等级，未知角色为 0
+func (r Role) Level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RolePublisher:
+		return 3
+	case RoleAdmin:
+		return 4
+	}
+	return 0
+}
+
+// AllNamespaces 角色绑定中表示全部命名空间
+const AllNamespaces = "*"
+
+// User Admin API 用户
+type User struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username     string    `json:"username" gorm:"size:64;not null;uniqueIndex"`
+	PasswordHash string    `json:"-" gorm:"size:256"`
+	Disabled     bool      `json:"disabled" gorm:"default:false"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (User) TableName() string {
+	return "admin_user"
+}
+
+// RoleBinding 用户在命名空间上的角色，namespace 为 * 表示全部命名空间
+type RoleBinding struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username  string    `json:"username" gorm:"size:64;not null;index:idx_binding_user_ns,unique"`
+	Namespace string    `json:"namespace" gorm:"size:64;not null;index:idx_binding_user_ns,unique"`
+	Role      Role      `json:"role" gorm:"size:20;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (RoleBinding) TableName() string {
+	return "role_binding"
+}
+
+// TokenKind 访问令牌类型
+type TokenKind string
+
+const (
+	TokenKindUser   TokenKind = "user"   // Admin API，代表某个用户
+	TokenKindClient TokenKind = "client" // 配置分发服务，限定可拉取的命名空间
+)
+
+// AccessToken 访问令牌，只保存令牌的 SHA-256
+type AccessToken struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind       TokenKind  `json:"kind" gorm:"size:20;not null;index"`
+	Name       string     `json:"name" gorm:"size:128"`
+	Username   string     `json:"username,omitempty" gorm:"size:64;index"` // user 令牌所属用户
+	Namespaces string     `json:"namespaces,omitempty" gorm:"size:1024"`   // client 令牌可访问的命名空间，逗号分隔，* 表示全部
+	TokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (AccessToken) TableName() string {
+	return "access_token"
+}
+
 // GrayRule 灰度规则
 type GrayRule struct {
 	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
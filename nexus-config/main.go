@@ -8,9 +8,10 @@ import (
 
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/krustd/gf-nexus/nexus-config/admin"
+	"github.com/krustd/gf-nexus/nexus-config/auth"
 	"github.com/krustd/gf-nexus/nexus-config/common"
 	"github.com/krustd/gf-nexus/nexus-config/server"
-	"github.com/krustd/gf-nexus/nexus-config/storage/sqlite"
+	"github.com/krustd/gf-nexus/nexus-config/storage/factory"
 )
 
 func main() {
@@ -28,7 +29,7 @@ func main() {
 	}
 
 	// 初始化存储
-	store, err := sqlite.NewSQLiteStorage(cfg.Database.FilePath)
+	store, err := factory.New(cfg.Database)
 	if err != nil {
 		g.Log().Fatalf(ctx, "初始化存储失败: %v", err)
 	}
@@ -43,9 +44,15 @@ func main() {
 	// 创建配置变更通知器
 	notifier := server.NewConfigNotifier()
 
+	// 鉴权（首次启用时创建管理员）
+	authn := auth.NewAuthenticator(store, cfg.Auth)
+	if err := authn.Bootstrap(ctx); err != nil {
+		g.Log().Fatalf(ctx, "初始化鉴权失败: %v", err)
+	}
+
 	// 启动 Admin API 服务（提供 Web UI + API）
 	adminServer := g.Server("admin")
-	admin.SetupRouter(adminServer, store, notifier)
+	admin.SetupRouter(adminServer, store, notifier, authn)
 	adminServer.SetAddr(cfg.Admin.Addr)
 	adminServer.SetDumpRouterMap(false)
 	go func() {
@@ -55,7 +62,7 @@ func main() {
 
 	// 启动配置分发服务
 	configServer := g.Server("config")
-	server.SetupRouter(configServer, store, notifier)
+	server.SetupRouter(configServer, store, notifier, authn)
 	configServer.SetAddr(cfg.Server.Addr)
 	configServer.SetDumpRouterMap(false)
 	go func() {
@@ -0,0 +1,129 @@
+package sdk
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/krustd/gf-nexus/nexus-config/common"
+)
+
+// TypedListener 类型化配置变更监听器，old 为切换前的值（首次加载时为 nil）
+type TypedListener[T any] func(old, new *T)
+
+// Binding 绑定到某个配置键的类型化配置：每个版本只解析一次，校验通过后原子替换
+type Binding[T any] struct {
+	client    *Client
+	namespace string
+	key       string
+	validate  func(*T) error
+	onError   func(error)
+
+	value atomic.Pointer[T]
+
+	mu        sync.Mutex // 串行化版本更新与监听器回调
+	md5       string     // 最近一次处理的版本（无论是否生效）
+	listeners []TypedListener[T]
+}
+
+// BindOption 绑定选项
+type BindOption[T any] func(*Binding[T])
+
+// WithValidator 设置校验函数，新版本校验失败时保留旧值并上报错误
+func WithValidator[T any](validate func(*T) error) BindOption[T] {
+	return func(b *Binding[T]) {
+		b.validate = validate
+	}
+}
+
+// WithErrorHandler 设置解析或校验失败时的错误处理，默认记录日志
+func WithErrorHandler[T any](onError func(error)) BindOption[T] {
+	return func(b *Binding[T]) {
+		b.onError = onError
+	}
+}
+
+// WithTypedListener 添加类型化变更监听器
+func WithTypedListener[T any](listener TypedListener[T]) BindOption[T] {
+	return func(b *Binding[T]) {
+		b.listeners = append(b.listeners, listener)
+	}
+}
+
+// Bind 订阅 namespace/key 并绑定为 T 类型。缓存中已有配置时立即解析，解析或校验失败时返回错误，
+// Binding 仍然有效并等待下一个版本；尚未拉取到配置时 Get 返回 nil
+func Bind[T any](c *Client, namespace, key string, opts ...BindOption[T]) (*Binding[T], error) {
+	b := &Binding[T]{client: c, namespace: namespace, key: key}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if err := c.Subscribe(namespace, key); err != nil {
+		log.Printf("[nexus-config] bind %s/%s: %v", namespace, key, err)
+	}
+	c.AddKeyChangeListener(namespace, key, func(*common.ConfigVersion) {
+		if err := b.update(); err != nil {
+			b.report(err)
+		}
+	})
+
+	if err := b.update(); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+// Get 返回当前配置，返回值只读，尚未加载时为 nil
+func (b *Binding[T]) Get() *T {
+	return b.value.Load()
+}
+
+// OnChange 添加类型化变更监听器
+func (b *Binding[T]) OnChange(listener TypedListener[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, listener)
+}
+
+// update 解析并校验客户端缓存中的最新版本，通过后替换当前值并通知监听器。
+// 变更回调在各自的 goroutine 中执行、到达顺序不确定，因此不使用回调携带的版本，
+// 而总是读取加锁时的最新版本，避免较旧的版本后到而覆盖新值
+func (b *Binding[T]) update() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	version, err := b.client.Get(b.namespace, b.key)
+	if err != nil {
+		// 尚未拉取到配置
+		return nil
+	}
+	if version.MD5 != "" && version.MD5 == b.md5 {
+		return nil
+	}
+	b.md5 = version.MD5
+
+	next := new(T)
+	if err := common.ParseConfig(version.Value, common.ConfigFormat(version.Format), next); err != nil {
+		return fmt.Errorf("parse %s/%s md5=%s: %w", b.namespace, b.key, version.MD5, err)
+	}
+	if b.validate != nil {
+		if err := b.validate(next); err != nil {
+			return fmt.Errorf("validate %s/%s md5=%s: %w", b.namespace, b.key, version.MD5, err)
+		}
+	}
+
+	old := b.value.Swap(next)
+	for _, listener := range b.listeners {
+		listener(old, next)
+	}
+	return nil
+}
+
+func (b *Binding[T]) report(err error) {
+	if b.onError != nil {
+		b.onError(err)
+		return
+	}
+	log.Printf("[nexus-config] bind: %v, keeping previous value", err)
+}
@@ -10,11 +10,13 @@ import (
 type ConfigCache struct {
 	mu      sync.RWMutex
 	configs map[string]*common.ConfigVersion // key: namespace/key
+	stale   map[string]bool                  // 来自本地快照、尚未与服务端确认的配置
 }
 
 func NewConfigCache() *ConfigCache {
 	return &ConfigCache{
 		configs: make(map[string]*common.ConfigVersion),
+		stale:   make(map[string]bool),
 	}
 }
 
@@ -35,6 +37,33 @@ func (c *ConfigCache) Set(version *common.ConfigVersion) {
 
 	configKey := version.Namespace + "/" + version.Key
 	c.configs[configKey] = version
+	delete(c.stale, configKey)
+}
+
+// SetStale 设置来自本地快照的配置，标记为过期，直到 Set 或 MarkFresh
+func (c *ConfigCache) SetStale(version *common.ConfigVersion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	configKey := version.Namespace + "/" + version.Key
+	c.configs[configKey] = version
+	c.stale[configKey] = true
+}
+
+// MarkFresh 服务端确认配置未变化，清除过期标记
+func (c *ConfigCache) MarkFresh(namespace, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.stale, namespace+"/"+key)
+}
+
+// IsStale 配置是否来自本地快照且尚未与服务端确认
+func (c *ConfigCache) IsStale(namespace, key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stale[namespace+"/"+key]
 }
 
 // Delete 删除缓存
@@ -44,6 +73,7 @@ func (c *ConfigCache) Delete(namespace, key string) {
 
 	configKey := namespace + "/" + key
 	delete(c.configs, configKey)
+	delete(c.stale, configKey)
 }
 
 // Clear 清空缓存
@@ -52,4 +82,5 @@ func (c *ConfigCache) Clear() {
 	defer c.mu.Unlock()
 
 	c.configs = make(map[string]*common.ConfigVersion)
+	c.stale = make(map[string]bool)
 }
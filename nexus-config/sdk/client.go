@@ -17,22 +17,40 @@ import (
 // ChangeListener 配置变更监听器
 type ChangeListener func(version *common.ConfigVersion)
 
-// Client 配置中心客户端
+// DiffListener 带字段级差异的配置变更监听器，changes 为与上一版本相比新增、删除、修改的配置项
+type DiffListener func(version *common.ConfigVersion, changes *common.ChangeSet)
+
+// configKey 订阅的配置键
+type configKey struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+func (k configKey) String() string {
+	return k.Namespace + "/" + k.Key
+}
+
+// Client 配置中心客户端，可订阅多个命名空间下的多个配置键，由同一个长轮询循环更新
 type Client struct {
 	cfg        *common.ClientConfig
 	cache      *ConfigCache
+	snapshot   *Snapshot    // 本地快照，未配置 SnapshotDir 时为 nil
 	httpClient *http.Client // 通用请求（fetchConfig 等）
 	pollClient *http.Client // 长轮询专用，无 ResponseHeaderTimeout 限制
 	listeners  map[string][]ChangeListener
+	diffs      map[string][]DiffListener
+	keys       []configKey // 已订阅的配置键，按订阅顺序
+	started    bool
+	pollCancel context.CancelFunc // 取消进行中的长轮询，订阅变化或停止时使用
 	mu         sync.RWMutex
 	stopCh     chan struct{}
 	wg         sync.WaitGroup
 }
 
-// NewClient 创建配置中心客户端
+// NewClient 创建配置中心客户端；配置了 Namespace 与 ConfigKey 时默认订阅该配置键
 func NewClient(cfg *common.ClientConfig) *Client {
 	pollTimeout := time.Duration(cfg.PollTimeout+10) * time.Second
-	return &Client{
+	c := &Client{
 		cfg:   cfg,
 		cache: NewConfigCache(),
 		httpClient: &http.Client{
@@ -43,16 +61,32 @@ func NewClient(cfg *common.ClientConfig) *Client {
 			Timeout:   pollTimeout,
 		},
 		listeners: make(map[string][]ChangeListener),
+		diffs:     make(map[string][]DiffListener),
 		stopCh:    make(chan struct{}),
 	}
+	if cfg.SnapshotDir != "" {
+		c.snapshot = NewSnapshot(cfg.SnapshotDir)
+	}
+	if cfg.Namespace != "" && cfg.ConfigKey != "" {
+		c.keys = append(c.keys, configKey{Namespace: cfg.Namespace, Key: cfg.ConfigKey})
+	}
+	return c
 }
 
-// Start 启动客户端（首次拉取 + 长轮询）
+// Start 启动客户端（首次拉取全部已订阅配置 + 长轮询）
 func (c *Client) Start(ctx context.Context) error {
-	log.Printf("[nexus-config] starting, namespace=%s, key=%s", c.cfg.Namespace, c.cfg.ConfigKey)
+	c.mu.Lock()
+	keys := append([]configKey(nil), c.keys...)
+	c.started = true
+	c.mu.Unlock()
+
+	log.Printf("[nexus-config] starting, keys=%v", keys)
 
-	if err := c.fetchConfig(ctx); err != nil {
-		log.Printf("[nexus-config] initial fetch failed: %v", err)
+	for _, k := range keys {
+		if err := c.fetchConfig(ctx, k); err != nil {
+			log.Printf("[nexus-config] initial fetch %s failed: %v", k, err)
+			c.loadSnapshot(k)
+		}
 	}
 
 	c.wg.Add(1)
@@ -64,19 +98,63 @@ func (c *Client) Start(ctx context.Context) error {
 // Stop 停止客户端
 func (c *Client) Stop() {
 	close(c.stopCh)
+	c.mu.Lock()
+	if c.pollCancel != nil {
+		c.pollCancel()
+	}
+	c.mu.Unlock()
 	c.wg.Wait()
 	log.Println("[nexus-config] client stopped")
 }
 
-// GetConfig 获取缓存中的配置
-func (c *Client) GetConfig() (*common.ConfigVersion, error) {
-	version, ok := c.cache.Get(c.cfg.Namespace, c.cfg.ConfigKey)
+// Subscribe 订阅配置键。客户端已启动时立即拉取一次并让长轮询带上新键；
+// 拉取失败时返回错误，订阅仍然保留，之后由长轮询获取
+func (c *Client) Subscribe(namespace, key string) error {
+	k := configKey{Namespace: namespace, Key: key}
+
+	c.mu.Lock()
+	for _, existing := range c.keys {
+		if existing == k {
+			c.mu.Unlock()
+			return nil
+		}
+	}
+	c.keys = append(c.keys, k)
+	started, cancel := c.started, c.pollCancel
+	c.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+	err := c.fetchConfig(context.Background(), k)
+	if err != nil {
+		c.loadSnapshot(k)
+	}
+	if cancel != nil {
+		cancel()
+	}
+	return err
+}
+
+// Get 获取缓存中 namespace/key 的配置
+func (c *Client) Get(namespace, key string) (*common.ConfigVersion, error) {
+	version, ok := c.cache.Get(namespace, key)
 	if !ok {
-		return nil, fmt.Errorf("config not found in cache")
+		return nil, fmt.Errorf("config %s/%s not found in cache", namespace, key)
 	}
 	return version, nil
 }
 
+// IsStale namespace/key 的配置是否来自本地快照且尚未与服务端确认，服务端恢复后自动清除
+func (c *Client) IsStale(namespace, key string) bool {
+	return c.cache.IsStale(namespace, key)
+}
+
+// GetConfig 获取缓存中的默认配置（cfg.Namespace/cfg.ConfigKey）
+func (c *Client) GetConfig() (*common.ConfigVersion, error) {
+	return c.Get(c.cfg.Namespace, c.cfg.ConfigKey)
+}
+
 // GetValue 获取配置内容字符串
 func (c *Client) GetValue() (string, error) {
 	version, err := c.GetConfig()
@@ -95,14 +173,27 @@ func (c *Client) GetValueAs(target interface{}) error {
 	return common.ParseConfig(version.Value, common.ConfigFormat(version.Format), target)
 }
 
-// AddChangeListener 添加配置变更监听器
+// AddChangeListener 添加默认配置的变更监听器
 func (c *Client) AddChangeListener(listener ChangeListener) {
+	c.AddKeyChangeListener(c.cfg.Namespace, c.cfg.ConfigKey, listener)
+}
+
+// AddKeyChangeListener 添加 namespace/key 的变更监听器，需配合 Subscribe 使用
+func (c *Client) AddKeyChangeListener(namespace, key string, listener ChangeListener) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	configKey := c.cfg.Namespace + "/" + c.cfg.ConfigKey
+	configKey := namespace + "/" + key
 	c.listeners[configKey] = append(c.listeners[configKey], listener)
 }
 
+// AddDiffListener 添加 namespace/key 的字段级变更监听器，新版本无法解析时不回调
+func (c *Client) AddDiffListener(namespace, key string, listener DiffListener) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	configKey := namespace + "/" + key
+	c.diffs[configKey] = append(c.diffs[configKey], listener)
+}
+
 func (c *Client) longPollLoop(ctx context.Context) {
 	defer c.wg.Done()
 	for {
@@ -115,42 +206,58 @@ func (c *Client) longPollLoop(ctx context.Context) {
 	}
 }
 
-// pollOnce 执行一次长轮询
+// pollOnce 执行一次长轮询：单个配置键使用 /poll，多个配置键合并为一次 /poll-batch
 func (c *Client) pollOnce(ctx context.Context) {
-	currentMD5 := ""
-	if version, ok := c.cache.Get(c.cfg.Namespace, c.cfg.ConfigKey); ok {
-		currentMD5 = version.MD5
-	}
+	pollCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	reqBody, _ := json.Marshal(map[string]interface{}{
-		"namespace": c.cfg.Namespace,
-		"key":       c.cfg.ConfigKey,
-		"client_id": c.cfg.ClientID,
-		"md5":       currentMD5,
-	})
+	c.mu.Lock()
+	keys := append([]configKey(nil), c.keys...)
+	c.pollCancel = cancel
+	c.mu.Unlock()
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		c.cfg.ServerAddr+"/api/v1/config/poll", bytes.NewReader(reqBody))
-	if err != nil {
-		log.Printf("[nexus-config] poll build request failed: %v", err)
-		time.Sleep(time.Duration(c.cfg.RetryDelay) * time.Second)
+	c.refreshStale(pollCtx, keys)
+
+	var err error
+	switch len(keys) {
+	case 0:
+		// 尚未订阅任何配置，等待 Subscribe 或 Stop
+		select {
+		case <-pollCtx.Done():
+		case <-c.stopCh:
+		}
 		return
+	case 1:
+		err = c.pollKey(pollCtx, keys[0])
+	default:
+		err = c.pollBatch(pollCtx, keys)
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.pollClient.Do(httpReq)
-	if err != nil {
-		log.Printf("[nexus-config] poll failed: %v", err)
-		time.Sleep(time.Duration(c.cfg.RetryDelay) * time.Second)
+	if err == nil {
+		return
+	}
+	if pollCtx.Err() != nil && ctx.Err() == nil {
+		// 订阅变化或停止，立即进入下一轮
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	log.Printf("[nexus-config] poll failed: %v", err)
+	select {
+	case <-c.stopCh:
+	case <-time.After(time.Duration(c.cfg.RetryDelay) * time.Second):
+	}
+}
+
+// pollKey 长轮询单个配置键
+func (c *Client) pollKey(ctx context.Context, k configKey) error {
+	body, err := c.post(ctx, c.pollClient, "/api/v1/config/poll", map[string]interface{}{
+		"namespace": k.Namespace,
+		"key":       k.Key,
+		"client_id": c.cfg.ClientID,
+		"md5":       c.currentMD5(k),
+		"timeout":   c.cfg.PollTimeout,
+	})
 	if err != nil {
-		log.Printf("[nexus-config] poll read body failed: %v", err)
-		time.Sleep(time.Duration(c.cfg.RetryDelay) * time.Second)
-		return
+		return err
 	}
 
 	var pollResp struct {
@@ -158,60 +265,205 @@ func (c *Client) pollOnce(ctx context.Context) {
 		Version *common.ConfigVersion `json:"version"`
 	}
 	if err := json.Unmarshal(body, &pollResp); err != nil {
-		log.Printf("[nexus-config] poll parse response failed: %v", err)
-		time.Sleep(time.Duration(c.cfg.RetryDelay) * time.Second)
-		return
+		return fmt.Errorf("parse response: %w", err)
 	}
 
 	if pollResp.Changed && pollResp.Version != nil {
-		log.Printf("[nexus-config] config changed: %s/%s md5=%s",
-			pollResp.Version.Namespace, pollResp.Version.Key, pollResp.Version.MD5)
-		c.cache.Set(pollResp.Version)
-		c.notifyListeners(pollResp.Version)
+		c.apply(pollResp.Version)
+	} else {
+		c.cache.MarkFresh(k.Namespace, k.Key)
 	}
+	return nil
 }
 
-// fetchConfig 立即拉取配置（非长轮询）
-func (c *Client) fetchConfig(ctx context.Context) error {
-	reqBody, _ := json.Marshal(map[string]interface{}{
-		"namespace": c.cfg.Namespace,
-		"key":       c.cfg.ConfigKey,
+// pollBatch 一次长轮询覆盖全部已订阅配置键，返回发生变化的配置
+func (c *Client) pollBatch(ctx context.Context, keys []configKey) error {
+	type pollKey struct {
+		configKey
+		MD5 string `json:"md5"`
+	}
+	items := make([]pollKey, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, pollKey{configKey: k, MD5: c.currentMD5(k)})
+	}
+
+	body, err := c.post(ctx, c.pollClient, "/api/v1/config/poll-batch", map[string]interface{}{
 		"client_id": c.cfg.ClientID,
+		"timeout":   c.cfg.PollTimeout,
+		"keys":      items,
 	})
+	if err != nil {
+		return err
+	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		c.cfg.ServerAddr+"/api/v1/config/get", bytes.NewReader(reqBody))
+	var pollResp struct {
+		Changes []*common.ConfigVersion `json:"changes"`
+	}
+	if err := json.Unmarshal(body, &pollResp); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	for _, version := range pollResp.Changes {
+		if version != nil {
+			c.apply(version)
+		}
+	}
+	// 未返回的配置与服务端一致
+	for _, k := range keys {
+		c.cache.MarkFresh(k.Namespace, k.Key)
+	}
+	return nil
+}
+
+func (c *Client) currentMD5(k configKey) string {
+	if version, ok := c.cache.Get(k.Namespace, k.Key); ok {
+		return version.MD5
+	}
+	return ""
+}
+
+// apply 更新缓存并通知监听器
+func (c *Client) apply(version *common.ConfigVersion) {
+	log.Printf("[nexus-config] config changed: %s/%s md5=%s", version.Namespace, version.Key, version.MD5)
+	old, _ := c.cache.Get(version.Namespace, version.Key)
+	c.accept(version)
+	c.notifyListeners(old, version)
+}
+
+// accept 写入缓存，配置了快照目录时同时保存快照
+func (c *Client) accept(version *common.ConfigVersion) {
+	c.cache.Set(version)
+	if c.snapshot == nil {
+		return
+	}
+	if err := c.snapshot.Save(version); err != nil {
+		log.Printf("[nexus-config] save snapshot %s/%s failed: %v", version.Namespace, version.Key, err)
+	}
+}
+
+// loadSnapshot 服务端不可用时从本地快照加载配置，标记为过期
+func (c *Client) loadSnapshot(k configKey) {
+	if c.snapshot == nil {
+		return
+	}
+	if _, ok := c.cache.Get(k.Namespace, k.Key); ok {
+		return
+	}
+	version, err := c.snapshot.Load(k.Namespace, k.Key)
 	if err != nil {
-		return fmt.Errorf("fetch config build request: %w", err)
+		log.Printf("[nexus-config] load snapshot %s failed: %v", k, err)
+		return
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
+	log.Printf("[nexus-config] using stale snapshot: %s md5=%s", k, version.MD5)
+	c.cache.SetStale(version)
+}
 
-	resp, err := c.httpClient.Do(httpReq)
+// refreshStale 长轮询前重新拉取来自快照的配置：服务端可达即清除过期标记，内容不同时按变更处理。
+// 不能依赖长轮询确认，快照与服务端一致时长轮询要等到超时才返回
+func (c *Client) refreshStale(ctx context.Context, keys []configKey) {
+	for _, k := range keys {
+		if !c.cache.IsStale(k.Namespace, k.Key) {
+			continue
+		}
+		version, err := c.getConfig(ctx, k)
+		if err != nil {
+			// 服务端仍不可用，由随后的长轮询失败重试
+			return
+		}
+		if version.MD5 == c.currentMD5(k) {
+			log.Printf("[nexus-config] stale snapshot confirmed: %s md5=%s", k, version.MD5)
+			c.cache.MarkFresh(k.Namespace, k.Key)
+			continue
+		}
+		c.apply(version)
+	}
+}
+
+// fetchConfig 立即拉取配置（非长轮询）
+func (c *Client) fetchConfig(ctx context.Context, k configKey) error {
+	version, err := c.getConfig(ctx, k)
 	if err != nil {
-		return fmt.Errorf("fetch config failed: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
+	log.Printf("[nexus-config] config fetched: %s/%s md5=%s", version.Namespace, version.Key, version.MD5)
+	c.accept(version)
+	return nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// getConfig 从服务端获取配置，不写入缓存
+func (c *Client) getConfig(ctx context.Context, k configKey) (*common.ConfigVersion, error) {
+	body, err := c.post(ctx, c.httpClient, "/api/v1/config/get", map[string]interface{}{
+		"namespace": k.Namespace,
+		"key":       k.Key,
+		"client_id": c.cfg.ClientID,
+	})
 	if err != nil {
-		return fmt.Errorf("fetch config read body: %w", err)
+		return nil, fmt.Errorf("fetch config failed: %w", err)
 	}
 
 	var version common.ConfigVersion
 	if err := json.Unmarshal(body, &version); err != nil {
-		return fmt.Errorf("fetch config parse response: %w", err)
+		return nil, fmt.Errorf("fetch config parse response: %w", err)
 	}
+	return &version, nil
+}
 
-	log.Printf("[nexus-config] config fetched: %s/%s md5=%s", version.Namespace, version.Key, version.MD5)
-	c.cache.Set(&version)
-	return nil
+// post 以 JSON 发送请求并返回响应体，非 200 响应视为错误
+func (c *Client) post(ctx context.Context, client *http.Client, path string, payload interface{}) ([]byte, error) {
+	reqBody, _ := json.Marshal(payload)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.ServerAddr+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status=%d body=%s", resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// setHeaders 设置请求头，配置了访问令牌时携带 Authorization
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.AccessToken)
+	}
 }
 
-func (c *Client) notifyListeners(version *common.ConfigVersion) {
+func (c *Client) notifyListeners(old, version *common.ConfigVersion) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	configKey := version.Namespace + "/" + version.Key
 	for _, listener := range c.listeners[configKey] {
 		go listener(version)
 	}
+
+	diffs := c.diffs[configKey]
+	if len(diffs) == 0 {
+		return
+	}
+	changes, err := common.DiffVersions(old, version)
+	if err != nil && old != nil {
+		// 上一版本无法解析时，视为全部新增
+		changes, err = common.DiffVersions(nil, version)
+	}
+	if err != nil {
+		log.Printf("[nexus-config] diff %s failed: %v", configKey, err)
+		return
+	}
+	for _, listener := range diffs {
+		go listener(version, changes)
+	}
 }
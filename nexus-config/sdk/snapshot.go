@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/krustd/gf-nexus/nexus-config/common"
+)
+
+// Snapshot 配置本地快照，服务端不可用时作为启动兜底
+type Snapshot struct {
+	dir string
+}
+
+// NewSnapshot 创建快照，dir 为快照根目录，每个配置保存为 dir/<namespace>/<key>.json
+func NewSnapshot(dir string) *Snapshot {
+	return &Snapshot{dir: dir}
+}
+
+func (s *Snapshot) path(namespace, key string) string {
+	return filepath.Join(s.dir, url.PathEscape(namespace), url.PathEscape(key)+".json")
+}
+
+// Save 保存配置快照：先写临时文件再重命名，进程中途退出也不会留下不完整的快照
+func (s *Snapshot) Save(version *common.ConfigVersion) error {
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(version.Namespace, version.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create snapshot dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*")
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close snapshot: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load 读取配置快照
+func (s *Snapshot) Load(namespace, key string) (*common.ConfigVersion, error) {
+	data, err := os.ReadFile(s.path(namespace, key))
+	if err != nil {
+		return nil, err
+	}
+
+	var version common.ConfigVersion
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+	if version.Namespace != namespace || version.Key != key {
+		return nil, fmt.Errorf("snapshot mismatch: %s/%s", version.Namespace, version.Key)
+	}
+	return &version, nil
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"hash/fnv"
 	"time"
 
@@ -11,6 +12,15 @@ import (
 	"github.com/krustd/gf-nexus/nexus-config/storage"
 )
 
+const (
+	// MaxBatchKeys 单次批量长轮询最多携带的配置键数量
+	MaxBatchKeys = 100
+	// DefaultPollTimeout 客户端未指定超时时的长轮询等待时间
+	DefaultPollTimeout = 30 * time.Second
+	// MaxPollTimeout 长轮询等待时间上限
+	MaxPollTimeout = 120 * time.Second
+)
+
 type Handler struct {
 	storage  storage.Storage
 	notifier *ConfigNotifier
@@ -28,7 +38,8 @@ type PollConfigReq struct {
 	Namespace string `json:"namespace" v:"required"`
 	Key       string `json:"key" v:"required"`
 	ClientID  string `json:"client_id" v:"required"`
-	MD5       string `json:"md5"` // 客户端当前配置的 MD5
+	MD5       string `json:"md5"`     // 客户端当前配置的 MD5
+	Timeout   int    `json:"timeout"` // 等待变更的秒数，为空时 30 秒，最大 120 秒
 }
 
 // PollConfigResp 长轮询响应
@@ -75,8 +86,7 @@ func (h *Handler) PollConfig(r *ghttp.Request) {
 	// MD5 相同，等待配置变更（长轮询）
 	g.Log().Infof(ctx, "config unchanged, waiting for change: %s/%s", req.Namespace, req.Key)
 
-	// 等待 30 秒
-	newVersion, changed := h.notifier.WaitForChange(ctx, req.Namespace, req.Key, 30*time.Second)
+	newVersion, changed := h.notifier.WaitForChange(ctx, req.Namespace, req.Key, pollTimeout(req.Timeout))
 
 	if changed {
 		// 重新计算灰度（因为可能灰度规则变了）
@@ -98,6 +108,106 @@ func (h *Handler) PollConfig(r *ghttp.Request) {
 	r.Response.WriteJson(&PollConfigResp{Changed: false})
 }
 
+// PollBatchKey 批量长轮询中的单个配置键
+type PollBatchKey struct {
+	Namespace string `json:"namespace" v:"required"`
+	Key       string `json:"key" v:"required"`
+	MD5       string `json:"md5"` // 客户端当前配置的 MD5
+}
+
+// PollBatchReq 批量长轮询请求
+type PollBatchReq struct {
+	ClientID string          `json:"client_id" v:"required"`
+	Keys     []*PollBatchKey `json:"keys" v:"required"`
+	Timeout  int             `json:"timeout"` // 等待变更的秒数，为空时 30 秒，最大 120 秒
+}
+
+// PollBatchResp 批量长轮询响应，Changes 为空表示超时未变更
+type PollBatchResp struct {
+	Changes []*common.ConfigVersion `json:"changes"`
+}
+
+// PollConfigBatch 批量长轮询：任一配置键 MD5 不一致时立即返回全部过期的配置，否则等待任一配置变更
+func (h *Handler) PollConfigBatch(r *ghttp.Request) {
+	var req PollBatchReq
+	if err := r.Parse(&req); err != nil {
+		r.Response.Status = 400
+		r.Response.WriteJson(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if len(req.Keys) > MaxBatchKeys {
+		r.Response.Status = 400
+		r.Response.WriteJson(map[string]interface{}{
+			"error": fmt.Sprintf("too many keys: %d, max %d", len(req.Keys), MaxBatchKeys),
+		})
+		return
+	}
+
+	ctx := r.GetCtx()
+
+	configKeys := make([]string, 0, len(req.Keys))
+	seen := make(map[string]bool, len(req.Keys))
+	for _, k := range req.Keys {
+		configKey := k.Namespace + "/" + k.Key
+		if !seen[configKey] {
+			seen[configKey] = true
+			configKeys = append(configKeys, configKey)
+		}
+	}
+
+	// 先订阅再比较 MD5，比较之后、等待之前的发布也会唤醒等待
+	ch := h.notifier.SubscribeAny(configKeys)
+	defer h.notifier.UnsubscribeAny(configKeys, ch)
+
+	if changes := h.staleVersions(ctx, &req); len(changes) > 0 {
+		r.Response.WriteJson(&PollBatchResp{Changes: changes})
+		return
+	}
+
+	g.Log().Infof(ctx, "%d configs unchanged, waiting for change", len(configKeys))
+
+	if _, changed := h.notifier.Wait(ctx, ch, pollTimeout(req.Timeout)); changed {
+		// 重新计算全部配置键（等待期间可能有多个配置变更，灰度规则也可能变化）
+		r.Response.WriteJson(&PollBatchResp{Changes: h.staleVersions(ctx, &req)})
+		return
+	}
+
+	// 超时，返回未变更
+	r.Response.WriteJson(&PollBatchResp{Changes: []*common.ConfigVersion{}})
+}
+
+// staleVersions 返回与客户端 MD5 不一致的配置版本，未发布的配置跳过
+func (h *Handler) staleVersions(ctx context.Context, req *PollBatchReq) []*common.ConfigVersion {
+	changes := make([]*common.ConfigVersion, 0)
+	for _, k := range req.Keys {
+		item, err := h.storage.GetPublishedConfig(ctx, k.Namespace, k.Key)
+		if err != nil {
+			continue
+		}
+		grayRule, err := h.storage.GetGrayRule(ctx, k.Namespace, k.Key)
+		isGrayEnabled := err == nil && grayRule.Enabled
+
+		version := h.calculateVersion(ctx, item, req.ClientID, isGrayEnabled, grayRule)
+		if version.MD5 != k.MD5 {
+			changes = append(changes, version)
+		}
+	}
+	return changes
+}
+
+// pollTimeout 将客户端指定的秒数换算为长轮询等待时间
+func pollTimeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		return DefaultPollTimeout
+	}
+	if timeout := time.Duration(seconds) * time.Second; timeout < MaxPollTimeout {
+		return timeout
+	}
+	return MaxPollTimeout
+}
+
 // calculateVersion 计算当前客户端应该使用的配置版本（含灰度计算）
 func (h *Handler) calculateVersion(ctx context.Context, item *common.ConfigItem, clientID string, isGrayEnabled bool, grayRule *common.GrayRule) *common.ConfigVersion {
 	// 默认使用已发布版本
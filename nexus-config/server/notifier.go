@@ -91,3 +91,62 @@ func (n *ConfigNotifier) WaitForChange(ctx context.Context, namespace, key strin
 		return nil, false
 	}
 }
+
+// SubscribeAny 以同一个 channel 订阅 configKeys（namespace/key）中任一配置的变更，
+// 用完须调用 UnsubscribeAny。先订阅再比较版本，比较与等待之间的发布不会丢失
+func (n *ConfigNotifier) SubscribeAny(configKeys []string) <-chan *common.ConfigVersion {
+	ch := make(chan *common.ConfigVersion, 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, configKey := range configKeys {
+		n.listeners[configKey] = append(n.listeners[configKey], ch)
+	}
+	return ch
+}
+
+// UnsubscribeAny 取消 SubscribeAny 的订阅
+func (n *ConfigNotifier) UnsubscribeAny(configKeys []string, ch <-chan *common.ConfigVersion) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var sendCh chan *common.ConfigVersion
+	for _, configKey := range configKeys {
+		listeners := n.listeners[configKey]
+		for i, listener := range listeners {
+			if listener == ch {
+				sendCh = listener
+				n.listeners[configKey] = append(listeners[:i], listeners[i+1:]...)
+				break
+			}
+		}
+		if len(n.listeners[configKey]) == 0 {
+			delete(n.listeners, configKey)
+		}
+	}
+	if sendCh != nil {
+		close(sendCh)
+	}
+}
+
+// Wait 等待 ch 上的变更（带超时）
+func (n *ConfigNotifier) Wait(ctx context.Context, ch <-chan *common.ConfigVersion, timeout time.Duration) (*common.ConfigVersion, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case version := <-ch:
+		return version, true
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// WaitForAnyChange 等待 configKeys（namespace/key）中任一配置变更（带超时），返回最先到达的变更
+func (n *ConfigNotifier) WaitForAnyChange(ctx context.Context, configKeys []string, timeout time.Duration) (*common.ConfigVersion, bool) {
+	ch := n.SubscribeAny(configKeys)
+	defer n.UnsubscribeAny(configKeys, ch)
+	return n.Wait(ctx, ch, timeout)
+}
@@ -2,11 +2,12 @@ package server
 
 import (
 	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/krustd/gf-nexus/nexus-config/auth"
 	"github.com/krustd/gf-nexus/nexus-config/storage"
 )
 
-// SetupRouter 设置配置分发服务路由
-func SetupRouter(s *ghttp.Server, store storage.Storage, notifier *ConfigNotifier) *Handler {
+// SetupRouter 设置配置分发服务路由；authn 非 nil 且开启 client_token 时要求客户端访问令牌
+func SetupRouter(s *ghttp.Server, store storage.Storage, notifier *ConfigNotifier, authn *auth.Authenticator) *Handler {
 	handler := NewHandler(store, notifier)
 
 	// 健康检查
@@ -14,8 +15,10 @@ func SetupRouter(s *ghttp.Server, store storage.Storage, notifier *ConfigNotifie
 
 	// 配置拉取 API
 	s.Group("/api/v1/config", func(group *ghttp.RouterGroup) {
-		group.POST("/poll", handler.PollConfig) // 长轮询
-		group.POST("/get", handler.GetConfig)   // 立即获取
+		group.Middleware(authn.ClientMiddleware)
+		group.POST("/poll", handler.PollConfig)            // 长轮询
+		group.POST("/poll-batch", handler.PollConfigBatch) // 批量长轮询
+		group.POST("/get", handler.GetConfig)              // 立即获取
 	})
 
 	return handler
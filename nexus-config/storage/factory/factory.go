@@ -0,0 +1,24 @@
+package factory
+
+import (
+	"fmt"
+
+	"github.com/krustd/gf-nexus/nexus-config/common"
+	"github.com/krustd/gf-nexus/nexus-config/storage"
+	"github.com/krustd/gf-nexus/nexus-config/storage/mysql"
+	"github.com/krustd/gf-nexus/nexus-config/storage/postgres"
+	"github.com/krustd/gf-nexus/nexus-config/storage/sqlite"
+)
+
+// New 按 Database.Type 创建存储实例，未配置时使用 SQLite
+func New(cfg common.DatabaseConfig) (storage.Storage, error) {
+	switch cfg.Type {
+	case "", "sqlite":
+		return sqlite.NewSQLiteStorage(cfg.FilePath)
+	case "mysql":
+		return mysql.NewMySQLStorage(cfg)
+	case "postgres", "postgresql":
+		return postgres.NewPostgresStorage(cfg)
+	}
+	return nil, fmt.Errorf("unsupported database type: %s", cfg.Type)
+}
@@ -0,0 +1,436 @@
+package gormstore
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/krustd/gf-nexus/nexus-config/common"
+	"github.com/krustd/gf-nexus/nexus-config/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+type gormStorage struct {
+	db *gorm.DB
+}
+
+// New 基于已打开的 gorm 连接创建存储实例，SQLite / MySQL / PostgreSQL 共用
+func New(db *gorm.DB) storage.Storage {
+	return &gormStorage{db: db}
+}
+
+// Config 各驱动共用的 gorm 设置
+func Config() *gorm.Config {
+	return &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	}
+}
+
+// notFound 将 gorm 的记录不存在错误转换为 storage.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.ErrNotFound
+	}
+	return err
+}
+
+// byKey 按命名空间与配置键过滤；key 是 MySQL 保留字，使用 map 条件由 gorm 转义列名
+func byKey(namespace, key string) map[string]interface{} {
+	return map[string]interface{}{"namespace": namespace, "key": key}
+}
+
+func (s *gormStorage) Init(ctx context.Context) error {
+	// 自动迁移表结构
+	return s.db.WithContext(ctx).AutoMigrate(
+		&common.ConfigNamespace{},
+		&common.ConfigItem{},
+		&common.GrayRule{},
+		&common.ConfigRelease{},
+		&common.AuditLog{},
+		&common.User{},
+		&common.RoleBinding{},
+		&common.AccessToken{},
+	)
+}
+
+func (s *gormStorage) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// === Namespace 操作 ===
+
+func (s *gormStorage) CreateNamespace(ctx context.Context, ns *common.ConfigNamespace) error {
+	ns.CreatedAt = time.Now()
+	ns.UpdatedAt = time.Now()
+	return s.db.WithContext(ctx).Create(ns).Error
+}
+
+func (s *gormStorage) GetNamespace(ctx context.Context, id string) (*common.ConfigNamespace, error) {
+	var ns common.ConfigNamespace
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&ns).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ns, nil
+}
+
+func (s *gormStorage) ListNamespaces(ctx context.Context) ([]*common.ConfigNamespace, error) {
+	var list []*common.ConfigNamespace
+	err := s.db.WithContext(ctx).Find(&list).Error
+	return list, err
+}
+
+func (s *gormStorage) DeleteNamespace(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 删除命名空间下的所有配置
+		if err := tx.Where("namespace = ?", id).Delete(&common.ConfigItem{}).Error; err != nil {
+			return err
+		}
+		// 删除命名空间下的所有灰度规则
+		if err := tx.Where("namespace = ?", id).Delete(&common.GrayRule{}).Error; err != nil {
+			return err
+		}
+		// 删除命名空间
+		return tx.Where("id = ?", id).Delete(&common.ConfigNamespace{}).Error
+	})
+}
+
+// === ConfigItem 操作 ===
+
+func (s *gormStorage) SaveDraft(ctx context.Context, namespace, key string, value string, format common.ConfigFormat) error {
+	now := time.Now()
+	item := common.ConfigItem{
+		Namespace:  namespace,
+		Key:        key,
+		Format:     format,
+		DraftValue: value,
+		DraftMD5:   fmt.Sprintf("%x", md5.Sum([]byte(value))),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	// 不存在则新建，存在则只更新草稿（单条语句，多个 Admin 实例并发保存时不会违反唯一索引）
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "namespace"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"draft_value", "draft_md5", "format", "updated_at"}),
+	}).Create(&item).Error
+}
+
+func (s *gormStorage) GetDraft(ctx context.Context, namespace, key string) (*common.ConfigItem, error) {
+	var item common.ConfigItem
+	err := s.db.WithContext(ctx).Where(byKey(namespace, key)).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *gormStorage) PublishConfig(ctx context.Context, namespace, key, publisher, comment string) (*common.ConfigRelease, error) {
+	var release *common.ConfigRelease
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item common.ConfigItem
+		if err := tx.Where(byKey(namespace, key)).First(&item).Error; err != nil {
+			return err
+		}
+
+		release = &common.ConfigRelease{
+			Namespace: namespace,
+			Key:       key,
+			Format:    item.Format,
+			Value:     item.DraftValue,
+			MD5:       item.DraftMD5,
+			Type:      common.ReleaseTypePublish,
+			Publisher: publisher,
+			Comment:   comment,
+		}
+		return s.release(tx, &item, release)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// release 将 release 的内容写为已发布配置并追加发布记录
+func (s *gormStorage) release(tx *gorm.DB, item *common.ConfigItem, release *common.ConfigRelease) error {
+	now := time.Now()
+	release.CreatedAt = now
+	if err := tx.Create(release).Error; err != nil {
+		return err
+	}
+	return tx.Model(item).Updates(map[string]interface{}{
+		"published_value": release.Value,
+		"published_md5":   release.MD5,
+		"published_at":    &now,
+		"updated_at":      now,
+	}).Error
+}
+
+func (s *gormStorage) GetPublishedConfig(ctx context.Context, namespace, key string) (*common.ConfigItem, error) {
+	var item common.ConfigItem
+	err := s.db.WithContext(ctx).Where(byKey(namespace, key)).Where("published_value != ''").First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *gormStorage) ListConfigs(ctx context.Context, namespace string) ([]*common.ConfigItem, error) {
+	var list []*common.ConfigItem
+	err := s.db.WithContext(ctx).Where("namespace = ?", namespace).Find(&list).Error
+	return list, err
+}
+
+// DeleteConfig 只删除配置项，发布记录保留，可通过回滚恢复
+func (s *gormStorage) DeleteConfig(ctx context.Context, namespace, key string) error {
+	return s.db.WithContext(ctx).Where(byKey(namespace, key)).Delete(&common.ConfigItem{}).Error
+}
+
+// === ConfigRelease 操作 ===
+
+func (s *gormStorage) ListReleases(ctx context.Context, namespace, key string, limit int) ([]*common.ConfigRelease, error) {
+	var list []*common.ConfigRelease
+	query := s.db.WithContext(ctx).Where(byKey(namespace, key)).Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+func (s *gormStorage) GetRelease(ctx context.Context, id int64) (*common.ConfigRelease, error) {
+	var release common.ConfigRelease
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&release).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &release, nil
+}
+
+func (s *gormStorage) Rollback(ctx context.Context, namespace, key string, releaseID int64, publisher, comment string) (*common.ConfigRelease, error) {
+	var release *common.ConfigRelease
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var target common.ConfigRelease
+		if err := tx.Where("id = ?", releaseID).Where(byKey(namespace, key)).First(&target).Error; err != nil {
+			return notFound(err)
+		}
+		var item common.ConfigItem
+		err := tx.Where(byKey(namespace, key)).First(&item).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 配置项已被删除：按发布记录重建，草稿同为回滚目标的内容
+			now := time.Now()
+			item = common.ConfigItem{
+				Namespace:  namespace,
+				Key:        key,
+				Format:     target.Format,
+				DraftValue: target.Value,
+				DraftMD5:   target.MD5,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			err = tx.Create(&item).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		release = &common.ConfigRelease{
+			Namespace:    namespace,
+			Key:          key,
+			Format:       target.Format,
+			Value:        target.Value,
+			MD5:          target.MD5,
+			Type:         common.ReleaseTypeRollback,
+			RollbackFrom: target.ID,
+			Publisher:    publisher,
+			Comment:      comment,
+		}
+		if err := s.release(tx, &item, release); err != nil {
+			return err
+		}
+		// 格式随回滚恢复
+		if item.Format != target.Format {
+			return tx.Model(&item).Update("format", target.Format).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// === GrayRule 操作 ===
+
+func (s *gormStorage) SaveGrayRule(ctx context.Context, rule *common.GrayRule) error {
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "namespace"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"percentage", "enabled", "updated_at"}),
+	}).Create(rule).Error
+}
+
+func (s *gormStorage) GetGrayRule(ctx context.Context, namespace, key string) (*common.GrayRule, error) {
+	var rule common.GrayRule
+	err := s.db.WithContext(ctx).Where(byKey(namespace, key)).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *gormStorage) DeleteGrayRule(ctx context.Context, namespace, key string) error {
+	return s.db.WithContext(ctx).Where(byKey(namespace, key)).Delete(&common.GrayRule{}).Error
+}
+
+func (s *gormStorage) ListGrayRules(ctx context.Context, namespace string) ([]*common.GrayRule, error) {
+	var list []*common.GrayRule
+	err := s.db.WithContext(ctx).Where("namespace = ?", namespace).Find(&list).Error
+	return list, err
+}
+
+// === 用户与权限 ===
+
+func (s *gormStorage) CreateUser(ctx context.Context, user *common.User) error {
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+	return s.db.WithContext(ctx).Create(user).Error
+}
+
+func (s *gormStorage) GetUser(ctx context.Context, username string) (*common.User, error) {
+	var user common.User
+	err := s.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *gormStorage) ListUsers(ctx context.Context) ([]*common.User, error) {
+	var list []*common.User
+	err := s.db.WithContext(ctx).Order("id").Find(&list).Error
+	return list, err
+}
+
+func (s *gormStorage) UpdateUser(ctx context.Context, user *common.User) error {
+	return s.db.WithContext(ctx).Model(&common.User{}).Where("username = ?", user.Username).Updates(map[string]interface{}{
+		"password_hash": user.PasswordHash,
+		"disabled":      user.Disabled,
+		"updated_at":    time.Now(),
+	}).Error
+}
+
+func (s *gormStorage) DeleteUser(ctx context.Context, username string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("username = ?", username).Delete(&common.RoleBinding{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("kind = ? AND username = ?", common.TokenKindUser, username).Delete(&common.AccessToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("username = ?", username).Delete(&common.User{}).Error
+	})
+}
+
+func (s *gormStorage) SaveRoleBinding(ctx context.Context, binding *common.RoleBinding) error {
+	binding.CreatedAt = time.Now()
+	binding.UpdatedAt = time.Now()
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}, {Name: "namespace"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(binding).Error
+}
+
+func (s *gormStorage) DeleteRoleBinding(ctx context.Context, username, namespace string) error {
+	return s.db.WithContext(ctx).Where("username = ? AND namespace = ?", username, namespace).Delete(&common.RoleBinding{}).Error
+}
+
+func (s *gormStorage) ListRoleBindings(ctx context.Context, username string) ([]*common.RoleBinding, error) {
+	var list []*common.RoleBinding
+	db := s.db.WithContext(ctx)
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+	err := db.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (s *gormStorage) CreateToken(ctx context.Context, token *common.AccessToken) error {
+	token.CreatedAt = time.Now()
+	return s.db.WithContext(ctx).Create(token).Error
+}
+
+func (s *gormStorage) GetTokenByHash(ctx context.Context, hash string) (*common.AccessToken, error) {
+	var token common.AccessToken
+	err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *gormStorage) ListTokens(ctx context.Context, kind common.TokenKind, username string) ([]*common.AccessToken, error) {
+	var list []*common.AccessToken
+	db := s.db.WithContext(ctx).Where("kind = ?", kind)
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+	err := db.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (s *gormStorage) DeleteToken(ctx context.Context, id int64) error {
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&common.AccessToken{}).Error
+}
+
+// === AuditLog 操作 ===
+
+func (s *gormStorage) AppendAudit(ctx context.Context, log *common.AuditLog) error {
+	log.CreatedAt = time.Now()
+	return s.db.WithContext(ctx).Create(log).Error
+}
+
+func (s *gormStorage) ListAudits(ctx context.Context, query *storage.AuditQuery) ([]*common.AuditLog, int64, error) {
+	db := s.db.WithContext(ctx).Model(&common.AuditLog{})
+	if query.Operator != "" {
+		db = db.Where("operator = ?", query.Operator)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.Namespace != "" {
+		db = db.Where("namespace = ?", query.Namespace)
+	}
+	if query.Key != "" {
+		db = db.Where(map[string]interface{}{"key": query.Key})
+	}
+	if !query.Since.IsZero() {
+		db = db.Where("created_at >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		db = db.Where("created_at < ?", query.Until)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []*common.AuditLog
+	err := db.Order("id DESC").
+		Offset((query.Page - 1) * query.PageSize).
+		Limit(query.PageSize).
+		Find(&list).Error
+	return list, total, err
+}
@@ -2,10 +2,15 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/krustd/gf-nexus/nexus-config/common"
 )
 
+// ErrNotFound 记录不存在，各实现须将自身的"未找到"错误转换为该错误
+var ErrNotFound = errors.New("record not found")
+
 // Storage 配置存储接口
 type Storage interface {
 	// Init 初始化存储（创建表等）
@@ -25,7 +30,7 @@ type Storage interface {
 	// ListNamespaces 列出所有命名空间
 	ListNamespaces(ctx context.Context) ([]*common.ConfigNamespace, error)
 
-	// DeleteNamespace 删除命名空间
+	// DeleteNamespace 删除命名空间及其配置项、灰度规则，发布记录保留
 	DeleteNamespace(ctx context.Context, id string) error
 
 	// === ConfigItem 操作 ===
@@ -36,8 +41,8 @@ type Storage interface {
 	// GetDraft 获取草稿
 	GetDraft(ctx context.Context, namespace, key string) (*common.ConfigItem, error)
 
-	// PublishConfig 发布配置（将草稿发布为正式版本）
-	PublishConfig(ctx context.Context, namespace, key string) error
+	// PublishConfig 发布配置（将草稿发布为正式版本），同时追加发布记录
+	PublishConfig(ctx context.Context, namespace, key, publisher, comment string) (*common.ConfigRelease, error)
 
 	// GetPublishedConfig 获取已发布的配置
 	GetPublishedConfig(ctx context.Context, namespace, key string) (*common.ConfigItem, error)
@@ -45,9 +50,21 @@ type Storage interface {
 	// ListConfigs 列出命名空间下的所有配置
 	ListConfigs(ctx context.Context, namespace string) ([]*common.ConfigItem, error)
 
-	// DeleteConfig 删除配置项
+	// DeleteConfig 删除配置项，发布记录保留
 	DeleteConfig(ctx context.Context, namespace, key string) error
 
+	// === ConfigRelease 操作 ===
+
+	// ListReleases 列出配置项的发布记录（按时间倒序），limit <= 0 表示不限制
+	ListReleases(ctx context.Context, namespace, key string, limit int) ([]*common.ConfigRelease, error)
+
+	// GetRelease 获取发布记录，不存在时返回 ErrNotFound
+	GetRelease(ctx context.Context, id int64) (*common.ConfigRelease, error)
+
+	// Rollback 将已发布配置恢复为指定发布记录的内容（草稿不变），并追加一条回滚记录；
+	// 配置项已被删除时按该记录重建，发布记录不存在时返回 ErrNotFound
+	Rollback(ctx context.Context, namespace, key string, releaseID int64, publisher, comment string) (*common.ConfigRelease, error)
+
 	// === GrayRule 操作 ===
 
 	// SaveGrayRule 保存灰度规则
@@ -61,4 +78,62 @@ type Storage interface {
 
 	// ListGrayRules 列出命名空间下的所有灰度规则
 	ListGrayRules(ctx context.Context, namespace string) ([]*common.GrayRule, error)
+
+	// === 用户与权限 ===
+
+	// CreateUser 创建用户
+	CreateUser(ctx context.Context, user *common.User) error
+
+	// GetUser 获取用户
+	GetUser(ctx context.Context, username string) (*common.User, error)
+
+	// ListUsers 列出所有用户
+	ListUsers(ctx context.Context) ([]*common.User, error)
+
+	// UpdateUser 更新用户密码与启用状态
+	UpdateUser(ctx context.Context, user *common.User) error
+
+	// DeleteUser 删除用户及其角色绑定、令牌
+	DeleteUser(ctx context.Context, username string) error
+
+	// SaveRoleBinding 保存角色绑定（同一用户同一命名空间只保留一条）
+	SaveRoleBinding(ctx context.Context, binding *common.RoleBinding) error
+
+	// DeleteRoleBinding 删除角色绑定
+	DeleteRoleBinding(ctx context.Context, username, namespace string) error
+
+	// ListRoleBindings 列出用户的角色绑定，username 为空时列出全部
+	ListRoleBindings(ctx context.Context, username string) ([]*common.RoleBinding, error)
+
+	// CreateToken 创建访问令牌
+	CreateToken(ctx context.Context, token *common.AccessToken) error
+
+	// GetTokenByHash 按令牌哈希查找
+	GetTokenByHash(ctx context.Context, hash string) (*common.AccessToken, error)
+
+	// ListTokens 按类型列出访问令牌，username 非空时只列出该用户的令牌
+	ListTokens(ctx context.Context, kind common.TokenKind, username string) ([]*common.AccessToken, error)
+
+	// DeleteToken 删除访问令牌
+	DeleteToken(ctx context.Context, id int64) error
+
+	// === AuditLog 操作 ===
+
+	// AppendAudit 追加审计记录
+	AppendAudit(ctx context.Context, log *common.AuditLog) error
+
+	// ListAudits 按条件分页查询审计记录（按时间倒序），返回当前页与总数
+	ListAudits(ctx context.Context, query *AuditQuery) ([]*common.AuditLog, int64, error)
+}
+
+// AuditQuery 审计记录查询条件，零值字段不参与过滤
+type AuditQuery struct {
+	Operator  string
+	Action    common.AuditAction
+	Namespace string
+	Key       string
+	Since     time.Time
+	Until     time.Time
+	Page      int // 从 1 开始
+	PageSize  int
 }
@@ -0,0 +1,41 @@
+package mysql
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	mysqldrv "github.com/go-sql-driver/mysql"
+	"github.com/krustd/gf-nexus/nexus-config/common"
+	"github.com/krustd/gf-nexus/nexus-config/storage"
+	"github.com/krustd/gf-nexus/nexus-config/storage/gormstore"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// NewMySQLStorage 创建 MySQL 存储实例
+func NewMySQLStorage(cfg common.DatabaseConfig) (storage.Storage, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = 3306
+	}
+	// 由驱动负责转义，用户名、密码中可以包含 @ / : ? 等字符
+	dc := mysqldrv.NewConfig()
+	dc.User = cfg.Username
+	dc.Passwd = cfg.Password
+	dc.Net = "tcp"
+	dc.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	dc.DBName = cfg.Database
+	dc.ParseTime = true
+	dc.Loc = time.Local
+	dc.Params = map[string]string{"charset": "utf8mb4"}
+	dsn := dc.FormatDSN()
+
+	db, err := gorm.Open(mysql.Open(dsn), gormstore.Config())
+	if err != nil {
+		return nil, fmt.Errorf("open mysql failed: %w", err)
+	}
+
+	return gormstore.New(db), nil
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/krustd/gf-nexus/nexus-config/common"
+	"github.com/krustd/gf-nexus/nexus-config/storage"
+	"github.com/krustd/gf-nexus/nexus-config/storage/gormstore"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// NewPostgresStorage 创建 PostgreSQL 存储实例
+func NewPostgresStorage(cfg common.DatabaseConfig) (storage.Storage, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = 5432
+	}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	// URL 形式的 DSN，各字段按 URL 规则转义
+	dsn := (&url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		Path:     "/" + cfg.Database,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}).String()
+
+	db, err := gorm.Open(postgres.Open(dsn), gormstore.Config())
+	if err != nil {
+		return nil, fmt.Errorf("open postgres failed: %w", err)
+	}
+
+	return gormstore.New(db), nil
+}
@@ -0,0 +1,7 @@
+import request from './request';
+import type { AuditPage, AuditQuery } from '@/types';
+
+// 查询审计日志
+export const listAudits = (params: AuditQuery) => {
+  return request.get<any, AuditPage>('/audit', { params });
+};
@@ -0,0 +1,23 @@
+import request, { TOKEN_KEY } from './request';
+import type { LoginRequest, LoginResponse, CurrentUser } from '@/types';
+
+// 登录，成功后保存令牌
+export const login = async (data: LoginRequest) => {
+  const resp = await request.post<any, LoginResponse>('/auth/login', data);
+  localStorage.setItem(TOKEN_KEY, resp.token);
+  return resp;
+};
+
+// 退出登录
+export const logout = async () => {
+  try {
+    await request.post('/auth/logout');
+  } finally {
+    localStorage.removeItem(TOKEN_KEY);
+  }
+};
+
+// 获取当前用户
+export const getCurrentUser = () => {
+  return request.get<any, CurrentUser>('/auth/me');
+};
@@ -1,5 +1,12 @@
 import request from './request';
-import type { ConfigVersion, ConfigItem, SaveDraftRequest, PublishConfigRequest } from '@/types';
+import type {
+  ConfigVersion,
+  ConfigItem,
+  ConfigRelease,
+  SaveDraftRequest,
+  PublishConfigRequest,
+  RollbackRequest,
+} from '@/types';
 
 // 保存草稿
 export const saveDraft = (data: SaveDraftRequest) => {
@@ -38,3 +45,15 @@ export const deleteConfig = (namespace: string, key: string) => {
     params: { namespace, key },
   });
 };
+
+// 获取发布历史
+export const listReleases = (namespace: string, key: string, limit?: number) => {
+  return request.get<any, ConfigRelease[]>('/configs/releases', {
+    params: { namespace, key, limit },
+  });
+};
+
+// 回滚到指定发布记录
+export const rollbackConfig = (data: RollbackRequest) => {
+  return request.post<any, ConfigRelease>('/configs/rollback', data);
+};
@@ -1,3 +1,5 @@
 export * from './namespace';
 export * from './config';
 export * from './gray';
+export * from './audit';
+export * from './auth';
@@ -10,9 +10,16 @@ const request: AxiosInstance = axios.create({
   },
 });
 
+// 登录令牌（Admin API 开启鉴权时使用）
+export const TOKEN_KEY = 'nexus-config-token';
+
 // 请求拦截器
 request.interceptors.request.use(
   (config) => {
+    const token = localStorage.getItem(TOKEN_KEY);
+    if (token) {
+      config.headers.Authorization = `Bearer ${token}`;
+    }
     return config;
   },
   (error) => {
@@ -37,6 +44,14 @@ request.interceptors.response.use(
     return res.data;
   },
   (error) => {
+    if (error.response?.status === 401 && !error.config?.url?.startsWith('/auth/login')) {
+      // 未登录或令牌过期，跳转登录页
+      localStorage.removeItem(TOKEN_KEY);
+      if (window.location.pathname !== '/login') {
+        window.location.href = '/login';
+      }
+      return Promise.reject(error);
+    }
     if (error.response) {
       const { status, data } = error.response;
       const errorMessage = data?.msg || data?.message || `请求失败 (${status})`;
@@ -0,0 +1,43 @@
+import React, { useState } from 'react';
+import { Button, Card, Form, Input } from 'antd';
+import { LockOutlined, UserOutlined } from '@ant-design/icons';
+import { useNavigate } from 'react-router-dom';
+import { login } from '@/api';
+import type { LoginRequest } from '@/types';
+
+const Login: React.FC = () => {
+  const [loading, setLoading] = useState(false);
+  const navigate = useNavigate();
+
+  const handleLogin = async (values: LoginRequest) => {
+    setLoading(true);
+    try {
+      await login(values);
+      navigate('/');
+    } catch (error) {
+      console.error('登录失败:', error);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div style={{ display: 'flex', justifyContent: 'center', alignItems: 'center', minHeight: '100vh' }}>
+      <Card title="Nexus Config 登录" style={{ width: 360 }}>
+        <Form onFinish={handleLogin}>
+          <Form.Item name="username" rules={[{ required: true, message: '请输入用户名' }]}>
+            <Input prefix={<UserOutlined />} placeholder="用户名" />
+          </Form.Item>
+          <Form.Item name="password" rules={[{ required: true, message: '请输入密码' }]}>
+            <Input.Password prefix={<LockOutlined />} placeholder="密码" />
+          </Form.Item>
+          <Button type="primary" htmlType="submit" loading={loading} block>
+            登录
+          </Button>
+        </Form>
+      </Card>
+    </div>
+  );
+};
+
+export default Login;
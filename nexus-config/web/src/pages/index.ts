@@ -1,3 +1,4 @@
 export { default as Namespaces } from './Namespaces';
 export { default as Configs } from './Configs';
 export { default as GrayRules } from './GrayRules';
+export { default as Login } from './Login';
@@ -65,6 +65,30 @@ export interface SaveDraftRequest {
 export interface PublishConfigRequest {
   namespace: string;
   key: string;
+  publisher?: string;
+  comment?: string;
+}
+
+export interface ConfigRelease {
+  id: number;
+  namespace: string;
+  key: string;
+  format: ConfigFormat;
+  value: string;
+  md5: string;
+  type: 'publish' | 'rollback';
+  rollback_from?: number;
+  publisher: string;
+  comment: string;
+  created_at: string;
+}
+
+export interface RollbackRequest {
+  namespace: string;
+  key: string;
+  release_id: number;
+  publisher?: string;
+  comment?: string;
 }
 
 export interface SaveGrayRuleRequest {
@@ -79,3 +103,51 @@ export interface ApiResponse<T = any> {
   message: string;
   data: T;
 }
+
+export interface AuditLog {
+  id: number;
+  operator: string;
+  action: string;
+  namespace: string;
+  key: string;
+  before: string;
+  after: string;
+  client_ip: string;
+  created_at: string;
+}
+
+export interface AuditQuery {
+  operator?: string;
+  action?: string;
+  namespace?: string;
+  key?: string;
+  since?: string;
+  until?: string;
+  page?: number;
+  page_size?: number;
+}
+
+export interface AuditPage {
+  list: AuditLog[];
+  total: number;
+  page: number;
+  page_size: number;
+}
+
+export interface LoginRequest {
+  username: string;
+  password: string;
+}
+
+export interface LoginResponse {
+  token: string;
+  expires_at?: string;
+}
+
+export type Role = 'viewer' | 'editor' | 'publisher' | 'admin';
+
+export interface CurrentUser {
+  auth_enabled: boolean;
+  username?: string;
+  roles?: Record<string, Role>;
+}
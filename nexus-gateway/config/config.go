@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
 )
 
 // GatewayConfig 本地 TOML 静态配置（启动时确定，极少变更）
@@ -15,20 +18,45 @@ type GatewayConfig struct {
 	Timeout      TimeoutConfig      `toml:"timeout"`
 	Metrics      MetricsConfig      `toml:"metrics"`
 	GRPC         GRPCConfig         `toml:"grpc"`
+	GRPCServer   GRPCServerConfig   `toml:"grpc_server"`
+	HealthCheck  HealthCheckConfig  `toml:"health_check"`
+	BackendTLS   BackendTLSConfig   `toml:"backend_tls"`
 }
 
-// RegistryConfig etcd 注册中心连接（配置中心的前置依赖）
+// RegistryConfig 注册中心连接（配置中心的前置依赖）
 type RegistryConfig struct {
+	Driver         string   `toml:"driver"` // etcd / memory / consul / file
 	Endpoints      []string `toml:"endpoints"`
 	DialTimeoutSec int      `toml:"dial_timeout"`
 	Prefix         string   `toml:"prefix"`
 	Username       string   `toml:"username,omitempty"`
 	Password       string   `toml:"password,omitempty"`
+	Token          string   `toml:"token,omitempty"` // consul ACL token
+	Path           string   `toml:"path,omitempty"`  // file 驱动的数据目录
 }
 
 // ServerConfig 服务监听地址
 type ServerConfig struct {
-	Addr string `toml:"addr"`
+	Addr string            `toml:"addr"`
+	TLS  ListenerTLSConfig `toml:"tls"`
+}
+
+// ListenerTLSConfig 网关监听的 TLS 终止：多证书按 SNI 选择，证书文件变更或配置中心发布新证书时热更新
+type ListenerTLSConfig struct {
+	Enabled        bool                `toml:"enabled"`
+	Addr           string              `toml:"addr"`             // HTTPS 监听地址；为空时 server.addr 改为 HTTPS，不再监听明文 HTTP
+	Certificates   []CertificateConfig `toml:"certificates"`     // 无 SNI 匹配时使用第一张
+	CertConfigKeys []string            `toml:"cert_config_keys"` // 配置中心键（与 config_center 同命名空间），值为 PEM 证书链 + 私钥
+	ClientCAFile   string              `toml:"client_ca_file"`   // 校验客户端证书的 CA
+	ClientAuth     string              `toml:"client_auth"`      // none / request / require / verify_if_given / require_and_verify，配置 client_ca_file 时默认 require_and_verify
+	RedirectHTTP   bool                `toml:"redirect_http"`    // server.addr 上的明文请求 301 重定向到 HTTPS（/health 除外）
+	ReloadInterval int                 `toml:"reload_interval"`  // 检查证书文件变更的周期（秒）
+}
+
+// CertificateConfig 证书文件，SNI 匹配依据证书中的域名（SAN）
+type CertificateConfig struct {
+	CertFile string `toml:"cert_file"`
+	KeyFile  string `toml:"key_file"`
 }
 
 // ConfigCenterConfig 配置中心 SDK 连接参数
@@ -39,6 +67,7 @@ type ConfigCenterConfig struct {
 	ClientID    string `toml:"client_id"`
 	PollTimeout int    `toml:"poll_timeout"`
 	RetryDelay  int    `toml:"retry_delay"`
+	AccessToken string `toml:"access_token"` // 配置中心开启 client_token 时的客户端访问令牌
 }
 
 type TimeoutConfig struct {
@@ -55,6 +84,41 @@ type GRPCConfig struct {
 	ReflectionCacheTTLSec int `toml:"reflection_cache_ttl_sec"`
 	ConnectTimeoutMs      int `toml:"connect_timeout_ms"`
 	RequestTimeoutMs      int `toml:"request_timeout_ms"`
+
+	// 预置描述符（protoc --include_imports --descriptor_set_out），已注册的服务不再依赖 server reflection
+	DescriptorSets       []string `toml:"descriptor_sets"`        // 本地文件路径，支持 glob
+	DescriptorConfigKeys []string `toml:"descriptor_config_keys"` // 配置中心键（与 config_center 同命名空间），值为 base64 编码的描述符集，变更实时生效
+}
+
+// GRPCServerConfig 原生 gRPC 透传监听（gRPC 进 gRPC 出），addr 为空表示不启用
+type GRPCServerConfig struct {
+	Addr          string `toml:"addr"`
+	ServiceHeader string `toml:"service_header"` // 指定目标服务的 metadata，缺省时取 :authority 的首段域名
+	CertFile      string `toml:"cert_file"`      // 同时配置 cert_file / key_file 时启用 TLS，否则为明文 h2c
+	KeyFile       string `toml:"key_file"`
+}
+
+// HealthCheckConfig 后端实例主动健康检查，字段含义见 registry.HealthCheckConfig
+type HealthCheckConfig struct {
+	Enabled bool `toml:"enabled"`
+	registry.HealthCheckConfig
+}
+
+// BackendTLSConfig 网关 → 后端（HTTP / gRPC）的 TLS：default 为全局设置，services 按服务名整体覆盖；
+// 实例 metadata 中 tls=true / false 覆盖 enabled，tls_server_name 覆盖 server_name
+type BackendTLSConfig struct {
+	Default  TLSClientConfig            `toml:"default"`
+	Services map[string]TLSClientConfig `toml:"services"`
+}
+
+// TLSClientConfig 单个服务的 TLS 客户端设置
+type TLSClientConfig struct {
+	Enabled            bool   `toml:"enabled"`
+	CAFile             string `toml:"ca_file"`              // 校验后端证书的 CA，空表示使用系统根证书
+	CertFile           string `toml:"cert_file"`            // 客户端证书（mTLS）
+	KeyFile            string `toml:"key_file"`             //
+	ServerName         string `toml:"server_name"`          // SNI 及证书校验域名，空表示使用实例地址
+	InsecureSkipVerify bool   `toml:"insecure_skip_verify"` // 跳过证书校验，仅用于开发环境
 }
 
 // ─── 动态配置（从配置中心获取，YAML 格式，支持热更新）───
@@ -67,6 +131,9 @@ type DynamicConfig struct {
 	Circuit   CircuitConfig   `yaml:"circuit"    json:"circuit"`
 	CORS      CORSConfig      `yaml:"cors"       json:"cors"`
 	Balancer  BalancerConfig  `yaml:"balancer"   json:"balancer"`
+	Outlier   OutlierConfig   `yaml:"outlier"    json:"outlier"`
+	Retry     RetryConfig     `yaml:"retry"      json:"retry"`
+	Routes    []RouteConfig   `yaml:"routes"     json:"routes"`
 }
 
 // JWTConfig 支持非对称加密 + JWKS 多密钥轮换
@@ -90,9 +157,26 @@ type IPFilterConfig struct {
 }
 
 type RateLimitConfig struct {
-	Enabled bool    `yaml:"enabled" json:"enabled"`
-	Rate    float64 `yaml:"rate"    json:"rate"`  // tokens per second
-	Burst   int     `yaml:"burst"   json:"burst"` // bucket capacity
+	Enabled        bool            `yaml:"enabled"          json:"enabled"`
+	Rate           float64         `yaml:"rate"             json:"rate"`             // tokens per second
+	Burst          int             `yaml:"burst"            json:"burst"`            // bucket capacity
+	Rules          []RateLimitRule `yaml:"rules"            json:"rules"`            // 细粒度规则，与全局令牌桶叠加生效
+	IdleTimeoutSec int             `yaml:"idle_timeout_sec" json:"idle_timeout_sec"` // 规则令牌桶空闲超过该时长后回收
+
+	// 分布式模式：多副本通过共享存储（默认 etcd）按滑动窗口计数，每窗口放行 rate × window_sec 个请求；
+	// 存储不可用时自动回退到本地令牌桶
+	Distributed bool `yaml:"distributed" json:"distributed"`
+	WindowSec   int  `yaml:"window_sec"  json:"window_sec"`
+}
+
+// RateLimitRule 按 key 分桶的限流规则，命中的所有规则都需放行请求才会被转发
+type RateLimitRule struct {
+	Name     string   `yaml:"name"     json:"name"`
+	Services []string `yaml:"services" json:"services"` // 生效的服务名，为空表示全部
+	Routes   []string `yaml:"routes"   json:"routes"`   // 生效的自定义路由名，为空表示不限
+	Key      string   `yaml:"key"      json:"key"`      // service / route / ip / header:<name> / claim:<name>
+	Rate     float64  `yaml:"rate"     json:"rate"`
+	Burst    int      `yaml:"burst"    json:"burst"`
 }
 
 type CircuitConfig struct {
@@ -116,6 +200,80 @@ type BalancerConfig struct {
 	Strategy string `yaml:"strategy" json:"strategy"` // round_robin / random / weighted_round_robin
 }
 
+// OutlierConfig 被动异常实例检测：连续失败的实例被临时驱逐，驱逐时长按次数指数增长
+type OutlierConfig struct {
+	Enabled            bool `yaml:"enabled"              json:"enabled"`
+	ConsecutiveErrors  int  `yaml:"consecutive_errors"   json:"consecutive_errors"`   // 连续失败 N 次触发驱逐
+	BaseEjectionSec    int  `yaml:"base_ejection_sec"    json:"base_ejection_sec"`    // 首次驱逐时长，第 n 次为 base * 2^(n-1)
+	MaxEjectionSec     int  `yaml:"max_ejection_sec"     json:"max_ejection_sec"`     // 单次驱逐时长上限
+	MaxEjectionPercent int  `yaml:"max_ejection_percent" json:"max_ejection_percent"` // 同一服务最多驱逐的实例比例（0-100）
+}
+
+// RetryConfig HTTP 代理重试策略：default 为全局策略，services 按服务名整体覆盖
+type RetryConfig struct {
+	Default  RetryPolicy            `yaml:"default"  json:"default"`
+	Services map[string]RetryPolicy `yaml:"services" json:"services"`
+}
+
+// RetryPolicy 单个服务的重试策略
+type RetryPolicy struct {
+	Enabled            bool     `yaml:"enabled"              json:"enabled"`
+	MaxAttempts        int      `yaml:"max_attempts"         json:"max_attempts"`         // 含首次请求的总尝试次数
+	RetryOn            []string `yaml:"retry_on"             json:"retry_on"`             // connect-error / timeout / 5xx / 具体状态码如 503
+	PerTryTimeoutMs    int      `yaml:"per_try_timeout_ms"   json:"per_try_timeout_ms"`   // 单次尝试超时，0 表示仅受整体响应超时约束
+	BackoffBaseMs      int      `yaml:"backoff_base_ms"      json:"backoff_base_ms"`      // 第 n 次重试前等待 [0, base*2^(n-1)) 随机时长
+	BackoffMaxMs       int      `yaml:"backoff_max_ms"       json:"backoff_max_ms"`       // 单次退避上限
+	RetryNonIdempotent bool     `yaml:"retry_non_idempotent" json:"retry_non_idempotent"` // 允许重试 POST / PATCH（连接失败始终可重试）
+	BufferLimitBytes   int64    `yaml:"buffer_limit_bytes"   json:"buffer_limit_bytes"`   // 请求体超过该大小时不重试
+}
+
+// Policy 返回指定服务生效的重试策略
+func (c RetryConfig) Policy(serviceName string) RetryPolicy {
+	if p, ok := c.Services[serviceName]; ok {
+		return p
+	}
+	return c.Default
+}
+
+func applyRetryDefaults(p *RetryPolicy) {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if len(p.RetryOn) == 0 {
+		p.RetryOn = []string{"connect-error", "502", "503", "504"}
+	}
+	if p.BackoffBaseMs <= 0 {
+		p.BackoffBaseMs = 25
+	}
+	if p.BackoffMaxMs <= 0 {
+		p.BackoffMaxMs = 250
+	}
+	if p.BufferLimitBytes <= 0 {
+		p.BufferLimitBytes = 1 << 20
+	}
+}
+
+// RouteConfig 自定义路由：按顺序匹配，命中第一条即转发到 service，未命中时回落到 /api/:service/*method
+type RouteConfig struct {
+	Name string `yaml:"name" json:"name"`
+
+	// 匹配条件（均为空表示不限制）
+	Hosts      []string          `yaml:"hosts"       json:"hosts"`       // 精确域名或 *.example.com
+	PathPrefix string            `yaml:"path_prefix" json:"path_prefix"` // 与 path_regex 二选一
+	PathRegex  string            `yaml:"path_regex"  json:"path_regex"`
+	Methods    []string          `yaml:"methods"     json:"methods"`
+	Headers    map[string]string `yaml:"headers"     json:"headers"` // 请求头精确匹配，值为空表示仅要求存在
+
+	// 目标
+	Service string `yaml:"service" json:"service"` // 注册中心中的服务名
+
+	// 动作
+	StripPrefix   bool              `yaml:"strip_prefix"   json:"strip_prefix"`   // 去掉 path_prefix 后转发
+	Rewrite       string            `yaml:"rewrite"        json:"rewrite"`        // 前缀匹配时替换前缀；正则匹配时为替换模板（支持 $1）
+	AddHeaders    map[string]string `yaml:"add_headers"    json:"add_headers"`    // 转发前设置的请求头
+	RemoveHeaders []string          `yaml:"remove_headers" json:"remove_headers"` // 转发前删除的请求头
+}
+
 // ─── 加载 & 默认值 ───
 
 type tomlRoot struct {
@@ -138,6 +296,9 @@ func LoadConfig(path string) (*GatewayConfig, error) {
 
 func applyDefaults(cfg *GatewayConfig) {
 	// Registry
+	if cfg.Registry.Driver == "" {
+		cfg.Registry.Driver = "etcd"
+	}
 	if len(cfg.Registry.Endpoints) == 0 {
 		cfg.Registry.Endpoints = []string{"127.0.0.1:2379"}
 	}
@@ -152,6 +313,9 @@ func applyDefaults(cfg *GatewayConfig) {
 	if cfg.Server.Addr == "" {
 		cfg.Server.Addr = ":8080"
 	}
+	if cfg.Server.TLS.ReloadInterval <= 0 {
+		cfg.Server.TLS.ReloadInterval = 10
+	}
 
 	// ConfigCenter
 	if cfg.ConfigCenter.Namespace == "" {
@@ -190,6 +354,11 @@ func applyDefaults(cfg *GatewayConfig) {
 	if cfg.GRPC.RequestTimeoutMs <= 0 {
 		cfg.GRPC.RequestTimeoutMs = 10000
 	}
+
+	// GRPCServer
+	if cfg.GRPCServer.ServiceHeader == "" {
+		cfg.GRPCServer.ServiceHeader = "x-nexus-service"
+	}
 }
 
 // ApplyDynamicDefaults 为动态配置填充默认值
@@ -201,6 +370,21 @@ func ApplyDynamicDefaults(cfg *DynamicConfig) {
 	if cfg.RateLimit.Burst <= 0 {
 		cfg.RateLimit.Burst = 2000
 	}
+	if cfg.RateLimit.WindowSec <= 0 {
+		cfg.RateLimit.WindowSec = 1
+	}
+	if cfg.RateLimit.IdleTimeoutSec <= 0 {
+		cfg.RateLimit.IdleTimeoutSec = 600
+	}
+	for i := range cfg.RateLimit.Rules {
+		rule := &cfg.RateLimit.Rules[i]
+		if rule.Key == "" {
+			rule.Key = "ip"
+		}
+		if rule.Burst <= 0 {
+			rule.Burst = int(math.Ceil(rule.Rate))
+		}
+	}
 
 	// Circuit
 	if cfg.Circuit.ErrorThreshold <= 0 {
@@ -234,4 +418,28 @@ func ApplyDynamicDefaults(cfg *DynamicConfig) {
 	if cfg.Balancer.Strategy == "" {
 		cfg.Balancer.Strategy = "round_robin"
 	}
+
+	// Outlier
+	if cfg.Outlier.ConsecutiveErrors <= 0 {
+		cfg.Outlier.ConsecutiveErrors = 5
+	}
+	if cfg.Outlier.BaseEjectionSec <= 0 {
+		cfg.Outlier.BaseEjectionSec = 30
+	}
+	if cfg.Outlier.MaxEjectionSec <= 0 {
+		cfg.Outlier.MaxEjectionSec = 300
+	}
+	if cfg.Outlier.MaxEjectionPercent <= 0 {
+		cfg.Outlier.MaxEjectionPercent = 10
+	}
+	if cfg.Outlier.MaxEjectionPercent > 100 {
+		cfg.Outlier.MaxEjectionPercent = 100
+	}
+
+	// Retry
+	applyRetryDefaults(&cfg.Retry.Default)
+	for name, p := range cfg.Retry.Services {
+		applyRetryDefaults(&p)
+		cfg.Retry.Services[name] = p
+	}
 }
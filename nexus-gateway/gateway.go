@@ -6,21 +6,30 @@ package nexus
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/krustd/gf-nexus/nexus-config/common"
 	"github.com/krustd/gf-nexus/nexus-config/sdk"
 	"github.com/krustd/gf-nexus/nexus-gateway/config"
 	"github.com/krustd/gf-nexus/nexus-gateway/gateway"
+	"github.com/krustd/gf-nexus/nexus-gateway/middleware"
 	"github.com/krustd/gf-nexus/nexus-registry/registry"
-	"github.com/krustd/gf-nexus/nexus-registry/registry/etcd"
+
+	// 内置注册中心驱动，通过 [gateway.registry] driver 选择
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/consul"
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/etcd"
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/file"
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/memory"
 )
 
 var (
 	gw           *gateway.Gateway
-	configClient *sdk.Client
+	configClient *sdk.Client // 动态配置与描述符集、证书等附属配置键共用同一个客户端
 	holder       *config.DynamicConfigHolder
 )
 
@@ -31,15 +40,18 @@ func Setup(configPath string) error {
 		return fmt.Errorf("nexus-gateway: load config: %w", err)
 	}
 
-	// 连接 etcd 注册中心
+	// 连接注册中心
 	regCfg := &registry.Config{
+		Driver:         cfg.Registry.Driver,
 		Endpoints:      cfg.Registry.Endpoints,
 		DialTimeoutSec: cfg.Registry.DialTimeoutSec,
 		Prefix:         cfg.Registry.Prefix,
 		Username:       cfg.Registry.Username,
 		Password:       cfg.Registry.Password,
+		Token:          cfg.Registry.Token,
+		Path:           cfg.Registry.Path,
 	}
-	reg, err := etcd.New(regCfg)
+	reg, err := registry.New(regCfg)
 	if err != nil {
 		return fmt.Errorf("nexus-gateway: create registry: %w", err)
 	}
@@ -66,7 +78,23 @@ func Setup(configPath string) error {
 		return fmt.Errorf("nexus-gateway: create gateway: %w", err)
 	}
 
-	log.Printf("[nexus-gateway] initialized, addr=%s", cfg.Server.Addr)
+	// 从配置中心加载 gRPC 描述符集与监听证书
+	if cfg.ConfigCenter.ServerAddr != "" {
+		for _, key := range cfg.GRPC.DescriptorConfigKeys {
+			watchDescriptorKey(cfg.ConfigCenter.Namespace, key, gw.Descriptors())
+		}
+		if certs := gw.Certificates(); certs != nil {
+			for _, key := range cfg.Server.TLS.CertConfigKeys {
+				watchCertKey(cfg.ConfigCenter.Namespace, key, certs)
+			}
+		}
+	}
+
+	if cfg.Server.TLS.Enabled {
+		log.Printf("[nexus-gateway] initialized, addr=%s https_addr=%s", cfg.Server.Addr, cfg.Server.TLS.Addr)
+	} else {
+		log.Printf("[nexus-gateway] initialized, addr=%s", cfg.Server.Addr)
+	}
 	return nil
 }
 
@@ -84,12 +112,20 @@ func setupConfigCenter(ccCfg config.ConfigCenterConfig) error {
 		ClientID:    clientID,
 		PollTimeout: ccCfg.PollTimeout,
 		RetryDelay:  ccCfg.RetryDelay,
+		AccessToken: ccCfg.AccessToken,
 	}
 
 	configClient = sdk.NewClient(sdkCfg)
 
-	// 注册配置变更监听器
-	configClient.AddChangeListener(func(version *common.ConfigVersion) {
+	// 启动客户端（首次拉取 + 长轮询）
+	if err := configClient.Start(context.Background()); err != nil {
+		return fmt.Errorf("start config client: %w", err)
+	}
+	if _, err := configClient.GetConfig(); err != nil {
+		log.Printf("[nexus-gateway] initial config fetch not available, using defaults: %v", err)
+	}
+
+	watchConfigKey(ccCfg.Namespace, ccCfg.ConfigKey, func(version *common.ConfigVersion) {
 		dynCfg := &config.DynamicConfig{}
 		if err := common.ParseConfig(version.Value, common.ConfigFormat(version.Format), dynCfg); err != nil {
 			log.Printf("[nexus-gateway] parse dynamic config failed: %v", err)
@@ -98,26 +134,81 @@ func setupConfigCenter(ccCfg config.ConfigCenterConfig) error {
 		holder.Store(dynCfg)
 		log.Printf("[nexus-gateway] dynamic config updated, md5=%s", version.MD5)
 	})
+	return nil
+}
 
-	// 启动客户端（首次拉取 + 长轮询）
-	if err := configClient.Start(context.Background()); err != nil {
-		return fmt.Errorf("start config client: %w", err)
-	}
+// watchDescriptorKey 订阅配置中心中 base64 编码的 FileDescriptorSet，变更时替换该键提供的服务描述符
+func watchDescriptorKey(namespace, key string, descs *gateway.DescriptorRegistry) {
+	source := "config:" + key
+	watchConfigKey(namespace, key, func(version *common.ConfigVersion) {
+		value := strings.TrimSpace(version.Value)
+		if value == "" {
+			descs.Remove(source)
+			log.Printf("[nexus-gateway] descriptor set %s removed", key)
+			return
+		}
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			log.Printf("[nexus-gateway] decode descriptor set %s failed: %v", key, err)
+			return
+		}
+		if err := descs.Register(source, data); err != nil {
+			log.Printf("[nexus-gateway] %v", err)
+			return
+		}
+		log.Printf("[nexus-gateway] descriptor set %s loaded, md5=%s", key, version.MD5)
+	})
+}
 
-	// 尝试将首次拉取的配置解析到 holder
-	if version, err := configClient.GetConfig(); err == nil {
-		dynCfg := &config.DynamicConfig{}
-		if err := common.ParseConfig(version.Value, common.ConfigFormat(version.Format), dynCfg); err != nil {
-			log.Printf("[nexus-gateway] parse initial dynamic config failed: %v", err)
-		} else {
-			holder.Store(dynCfg)
-			log.Println("[nexus-gateway] initial dynamic config loaded from config center")
+// watchCertKey 订阅配置中心中的 PEM 证书（证书链 + 私钥），变更时替换该键提供的监听证书
+func watchCertKey(namespace, key string, certs *gateway.CertStore) {
+	source := "config:" + key
+	watchConfigKey(namespace, key, func(version *common.ConfigVersion) {
+		if strings.TrimSpace(version.Value) == "" {
+			if err := certs.Remove(source); err != nil {
+				log.Printf("[nexus-gateway] remove certificate %s failed: %v", key, err)
+				return
+			}
+			log.Printf("[nexus-gateway] certificate %s removed", key)
+			return
 		}
-	} else {
-		log.Printf("[nexus-gateway] initial config fetch not available, using defaults: %v", err)
+		if err := certs.Publish(source, []byte(version.Value)); err != nil {
+			log.Printf("[nexus-gateway] %v", err)
+			return
+		}
+		log.Printf("[nexus-gateway] certificate %s loaded, md5=%s", key, version.MD5)
+	})
+}
+
+// watchConfigKey 在 configClient 上订阅配置键，首次可用及每次变更时调用 apply。
+// 变更回调在各自的 goroutine 中执行、到达顺序不确定，因此 apply 总是取加锁时缓存中的最新版本，
+// 同一版本只调用一次，避免较旧的版本后到而覆盖新值
+func watchConfigKey(namespace, key string, apply func(*common.ConfigVersion)) {
+	var (
+		mu      sync.Mutex
+		applied string
+	)
+	update := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		version, err := configClient.Get(namespace, key)
+		if err != nil {
+			return
+		}
+		if version.MD5 != "" && version.MD5 == applied {
+			return
+		}
+		applied = version.MD5
+		apply(version)
 	}
 
-	return nil
+	configClient.AddKeyChangeListener(namespace, key, func(*common.ConfigVersion) {
+		update()
+	})
+	if err := configClient.Subscribe(namespace, key); err != nil {
+		log.Printf("[nexus-gateway] config key %s not available: %v", key, err)
+	}
+	update()
 }
 
 // MustSetup 同 Setup，失败 panic
@@ -127,6 +218,14 @@ func MustSetup(configPath string) {
 	}
 }
 
+// SetRateLimitStore 替换分布式限流的共享存储（默认 etcd 驱动下复用注册中心连接），需在 Start 之前调用
+func SetRateLimitStore(store middleware.RateLimitStore) {
+	if gw == nil {
+		panic("nexus-gateway: not initialized, call Setup first")
+	}
+	gw.SetRateLimitStore(store)
+}
+
 // Start 启动网关（阻塞）
 func Start() {
 	if gw == nil {
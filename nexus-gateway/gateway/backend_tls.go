@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
+)
+
+// 实例 metadata 中的 TLS 开关
+const (
+	metaTLS           = "tls"             // true / false，覆盖网关配置中的 enabled
+	metaTLSServerName = "tls_server_name" // 覆盖 SNI / 证书校验域名
+)
+
+// backendTLS 网关 → 后端的 TLS 设置，按服务选择配置，实例 metadata 可覆盖开关和 SNI
+type backendTLS struct {
+	cfg  config.BackendTLSConfig
+	base map[string]*tls.Config // 服务名（"" 为默认）→ 由配置文件构建的 TLS 设置
+
+	mu      sync.Mutex
+	derived map[string]*tls.Config // 服务名 + SNI → 覆盖 ServerName 后的设置
+}
+
+// newBackendTLS 启动时加载全部证书，证书不可用时返回错误
+func newBackendTLS(cfg config.BackendTLSConfig) (*backendTLS, error) {
+	b := &backendTLS{
+		cfg:     cfg,
+		base:    make(map[string]*tls.Config),
+		derived: make(map[string]*tls.Config),
+	}
+	tc, err := buildTLSConfig(cfg.Default)
+	if err != nil {
+		return nil, fmt.Errorf("nexus-gateway: backend tls default: %w", err)
+	}
+	b.base[""] = tc
+	for name, sc := range cfg.Services {
+		tc, err := buildTLSConfig(sc)
+		if err != nil {
+			return nil, fmt.Errorf("nexus-gateway: backend tls %s: %w", name, err)
+		}
+		b.base[name] = tc
+	}
+	return b, nil
+}
+
+func buildTLSConfig(c config.TLSClientConfig) (*tls.Config, error) {
+	tc := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca %s: %w", c.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", c.CAFile)
+		}
+		tc.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client cert: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
+}
+
+// forInstance 返回访问该实例使用的 TLS 设置，nil 表示明文
+func (b *backendTLS) forInstance(serviceName string, inst *registry.ServiceInstance) *tls.Config {
+	if b == nil {
+		return nil
+	}
+	sc, ok := b.cfg.Services[serviceName]
+	key := serviceName
+	if !ok {
+		sc, key = b.cfg.Default, ""
+	}
+
+	enabled := sc.Enabled
+	switch inst.Metadata[metaTLS] {
+	case "true":
+		enabled = true
+	case "false":
+		enabled = false
+	}
+	if !enabled {
+		return nil
+	}
+
+	tc := b.base[key]
+	serverName := inst.Metadata[metaTLSServerName]
+	if serverName == "" || serverName == tc.ServerName {
+		return tc
+	}
+
+	// 按 SNI 派生并缓存，保证同一设置复用同一连接池
+	dk := key + "|" + serverName
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if d, ok := b.derived[dk]; ok {
+		return d
+	}
+	d := tc.Clone()
+	d.ServerName = serverName
+	b.derived[dk] = d
+	return d
+}
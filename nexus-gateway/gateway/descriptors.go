@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorRegistry 预置的 gRPC 服务描述符（protoc --descriptor_set_out 生成的 FileDescriptorSet），
+// 按服务全名查找；后端关闭 server reflection 时，转码代理依赖此处注册的描述符
+type DescriptorRegistry struct {
+	mu       sync.RWMutex
+	services map[string]*desc.ServiceDescriptor // 服务全名 → 描述符
+	sources  map[string][]string                // 来源（文件路径 / 配置键）→ 该来源提供的服务全名
+
+	version atomic.Uint64 // 每次变更递增，用于使 REST 路由表缓存失效
+}
+
+func NewDescriptorRegistry() *DescriptorRegistry {
+	return &DescriptorRegistry{
+		services: make(map[string]*desc.ServiceDescriptor),
+		sources:  make(map[string][]string),
+	}
+}
+
+// LoadFiles 加载匹配 patterns（支持 glob）的全部描述符文件
+func (dr *DescriptorRegistry) LoadFiles(patterns []string) error {
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("nexus-gateway: descriptor set pattern %s: %w", pattern, err)
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("nexus-gateway: descriptor set %s: no such file", pattern)
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("nexus-gateway: read descriptor set %s: %w", path, err)
+			}
+			if err := dr.Register(path, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Register 解析序列化的 FileDescriptorSet，替换 source 此前提供的全部服务；
+// 描述符集须包含全部依赖（protoc --include_imports）
+func (dr *DescriptorRegistry) Register(source string, data []byte) error {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("nexus-gateway: parse descriptor set %s: %w", source, err)
+	}
+	files, err := desc.CreateFileDescriptorsFromSet(&set)
+	if err != nil {
+		return fmt.Errorf("nexus-gateway: build descriptors %s: %w", source, err)
+	}
+
+	services := make(map[string]*desc.ServiceDescriptor)
+	for _, fd := range files {
+		for _, sd := range fd.GetServices() {
+			services[sd.GetFullyQualifiedName()] = sd
+		}
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	for _, name := range dr.sources[source] {
+		delete(dr.services, name)
+	}
+	names := make([]string, 0, len(services))
+	for name, sd := range services {
+		dr.services[name] = sd
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dr.sources[source] = names
+	dr.version.Add(1)
+	return nil
+}
+
+// Remove 移除 source 提供的全部服务
+func (dr *DescriptorRegistry) Remove(source string) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	for _, name := range dr.sources[source] {
+		delete(dr.services, name)
+	}
+	delete(dr.sources, source)
+	dr.version.Add(1)
+}
+
+// FindService 按服务全名（如 user.v1.UserService）查找，未注册时返回 nil
+func (dr *DescriptorRegistry) FindService(name string) *desc.ServiceDescriptor {
+	if dr == nil {
+		return nil
+	}
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+	return dr.services[name]
+}
+
+// Services 返回全部已注册服务（按名称排序）
+func (dr *DescriptorRegistry) Services() []*desc.ServiceDescriptor {
+	if dr == nil {
+		return nil
+	}
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+	names := make([]string, 0, len(dr.services))
+	for name := range dr.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]*desc.ServiceDescriptor, 0, len(names))
+	for _, name := range names {
+		list = append(list, dr.services[name])
+	}
+	return list
+}
+
+// Version 返回变更版本号
+func (dr *DescriptorRegistry) Version() uint64 {
+	if dr == nil {
+		return 0
+	}
+	return dr.version.Load()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/krustd/gf-nexus/nexus-gateway/metrics"
 	"github.com/krustd/gf-nexus/nexus-gateway/middleware"
 	"github.com/krustd/gf-nexus/nexus-registry/registry"
+	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
 // Gateway API 网关核心
@@ -22,23 +23,75 @@ type Gateway struct {
 	server    *ghttp.Server
 	grpcProxy *GRPCProxy
 	keyMgr    *middleware.KeyManager
+	outlier   *OutlierDetector
+	router    *Router
+	rlStore   middleware.RateLimitStore
+	grpcPass  *grpcPassthrough
+	descs     *DescriptorRegistry
+	tls       *backendTLS
+	certs     *CertStore
 }
 
 func New(cfg *config.GatewayConfig, holder *config.DynamicConfigHolder, reg registry.Registry) (*Gateway, error) {
 	dynCfg := holder.Load()
 	pickerFactory := newPickerFactory(dynCfg.Balancer.Strategy)
-	pool := NewResolverPool(reg, pickerFactory)
+
+	// 被动异常检测：按动态配置实时启停，过滤器始终挂载
+	od := NewOutlierDetector(holder)
 
 	// 创建 JWT 密钥管理器
 	km := middleware.NewKeyManager()
 	km.UpdateKeys(dynCfg.JWT.Keys)
 
+	// 预置 gRPC 描述符
+	descs := NewDescriptorRegistry()
+	if err := descs.LoadFiles(cfg.GRPC.DescriptorSets); err != nil {
+		return nil, err
+	}
+
+	// 后端 TLS
+	backendTLS, err := newBackendTLS(cfg.BackendTLS)
+	if err != nil {
+		return nil, err
+	}
+
+	// 主动健康检查与业务流量使用相同的后端 TLS 设置
+	resolverOpts := []registry.ResolverOption{registry.WithInstanceFilter(od.Filter)}
+	if cfg.HealthCheck.Enabled {
+		hcCfg := cfg.HealthCheck.HealthCheckConfig
+		hcCfg.TLSConfig = backendTLS.forInstance
+		resolverOpts = append(resolverOpts, registry.WithHealthCheck(hcCfg))
+	}
+	pool := NewResolverPool(reg, pickerFactory, resolverOpts...)
+
+	// 监听 TLS 证书
+	var certs *CertStore
+	if cfg.Server.TLS.Enabled {
+		if certs, err = NewCertStore(cfg.Server.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	// 自定义路由表
+	router := NewRouter()
+	router.UpdateRoutes(dynCfg.Routes)
+
 	gw := &Gateway{
-		config: cfg,
-		holder: holder,
-		pool:   pool,
-		reg:    reg,
-		keyMgr: km,
+		config:  cfg,
+		holder:  holder,
+		pool:    pool,
+		reg:     reg,
+		keyMgr:  km,
+		outlier: od,
+		router:  router,
+		descs:   descs,
+		tls:     backendTLS,
+		certs:   certs,
+	}
+
+	// 分布式限流默认复用注册中心的 etcd 连接
+	if ec, ok := reg.(interface{ Client() *clientv3.Client }); ok {
+		gw.rlStore = middleware.NewEtcdRateLimitStore(ec.Client(), "")
 	}
 
 	// 注册动态配置变更回调
@@ -46,6 +99,9 @@ func New(cfg *config.GatewayConfig, holder *config.DynamicConfigHolder, reg regi
 		// 更新 JWT 密钥
 		km.UpdateKeys(newCfg.JWT.Keys)
 
+		// 更新路由表
+		router.UpdateRoutes(newCfg.Routes)
+
 		// 更新负载均衡策略
 		if newCfg.Balancer.Strategy != "" {
 			pool.UpdateStrategy(newCfg.Balancer.Strategy)
@@ -55,20 +111,59 @@ func New(cfg *config.GatewayConfig, holder *config.DynamicConfigHolder, reg regi
 	return gw, nil
 }
 
+// Descriptors 返回预置 gRPC 描述符注册表，可在运行期注册或替换描述符
+func (gw *Gateway) Descriptors() *DescriptorRegistry {
+	return gw.descs
+}
+
+// Certificates 返回 HTTPS 监听的证书集合，未启用 TLS 时返回 nil
+func (gw *Gateway) Certificates() *CertStore {
+	return gw.certs
+}
+
+// SetRateLimitStore 替换分布式限流使用的共享存储，需在 Start 之前调用
+func (gw *Gateway) SetRateLimitStore(store middleware.RateLimitStore) {
+	gw.rlStore = store
+}
+
 // Start 启动网关（阻塞）
 func (gw *Gateway) Start() {
 	s := g.Server("gateway")
 	s.SetAddr(gw.config.Server.Addr)
 	gw.server = s
 
+	// TLS 终止：addr 为空时 server.addr 改为 HTTPS
+	tlsCfg := gw.config.Server.TLS
+	if gw.certs != nil {
+		serverTLS := gw.certs.ServerConfig()
+		if serverTLS == nil {
+			g.Log().Fatalf(context.Background(), "[gateway] no server certificate available")
+		}
+		s.SetTLSConfig(serverTLS)
+		if tlsCfg.Addr != "" {
+			s.SetHTTPSAddr(tlsCfg.Addr)
+		}
+		go gw.certs.watch(time.Duration(tlsCfg.ReloadInterval) * time.Second)
+	}
+
+	// 策略状态由 HTTP 入口与 gRPC 透传入口共享
+	ipm := middleware.NewIPMatcher(gw.holder)
+	limiter := middleware.NewGlobalRateLimiter(gw.holder, gw.rlStore)
+	cb := middleware.NewCircuitBreakerManager(gw.holder)
+	rl := middleware.NewRateLimitManager(gw.holder, gw.rlStore)
+	gw.grpcProxy = NewGRPCProxy(gw.config.GRPC, gw.descs, gw.holder)
+
 	// 全局中间件链（顺序重要）
+	if gw.certs != nil && tlsCfg.Addr != "" && tlsCfg.RedirectHTTP {
+		s.Use(httpsRedirect(tlsCfg.Addr))
+	}
 	s.Use(
 		middleware.Trace(),
 		middleware.RequestID(),
 		middleware.Logging(),
 		middleware.CORS(gw.holder),
-		middleware.IPFilter(gw.holder),
-		middleware.RateLimit(gw.holder),
+		middleware.IPFilter(ipm),
+		middleware.RateLimit(limiter),
 		middleware.JWT(gw.holder, gw.keyMgr),
 	)
 
@@ -86,12 +181,13 @@ func (gw *Gateway) Start() {
 	}
 
 	// 泛化调用路由
-	gw.grpcProxy = NewGRPCProxy(gw.config.GRPC)
-	proxy := NewProxyHandler(gw.pool, gw.config.Timeout, gw.grpcProxy)
-	cb := middleware.NewCircuitBreakerManager(gw.holder)
+	proxy := NewProxyHandler(gw.pool, gw.config.Timeout, gw.holder, gw.grpcProxy, gw.outlier, gw.tls)
 
-	s.BindHandler("ALL:/api/:service/*method", func(r *ghttp.Request) {
-		serviceName := r.GetRouter("service").String()
+	forward := func(r *ghttp.Request, serviceName, routeName, method string) {
+		// 按规则限流（服务 / 路由 / IP / Header / JWT claim）
+		if !rl.Allow(r, serviceName, routeName) {
+			return
+		}
 
 		// 熔断检查
 		if cb.Enabled() && !cb.Allow(serviceName) {
@@ -100,7 +196,7 @@ func (gw *Gateway) Start() {
 		}
 
 		// 执行代理
-		proxy.Handle(r)
+		proxy.Forward(r, serviceName, method)
 
 		// 记录熔断指标
 		if cb.Enabled() {
@@ -111,16 +207,60 @@ func (gw *Gateway) Start() {
 				cb.RecordSuccess(serviceName)
 			}
 		}
+	}
+
+	// 自定义路由优先，未命中时按 /api/:service/*method 泛化调用
+	s.BindHandler("ALL:/api/:service/*method", func(r *ghttp.Request) {
+		if m, ok := gw.router.Match(r.Request); ok {
+			m.ApplyHeaders(r.Request.Header)
+			forward(r, m.Service, m.Route.Name, m.Path)
+			return
+		}
+		forward(r, r.GetRouter("service").String(), "", r.GetRouter("method").String())
+	})
+	s.BindHandler("ALL:/*path", func(r *ghttp.Request) {
+		if m, ok := gw.router.Match(r.Request); ok {
+			m.ApplyHeaders(r.Request.Header)
+			forward(r, m.Service, m.Route.Name, m.Path)
+			return
+		}
+		GatewayError(r, CodeRouteNotFound, "no route for "+r.URL.Path)
 	})
 
+	// 原生 gRPC 透传
+	if gw.config.GRPCServer.Addr != "" {
+		gw.grpcPass = &grpcPassthrough{
+			cfg:     gw.config.GRPCServer,
+			holder:  gw.holder,
+			pool:    gw.pool,
+			conns:   gw.grpcProxy,
+			tls:     gw.tls,
+			outlier: gw.outlier,
+			keyMgr:  gw.keyMgr,
+			ipm:     ipm,
+			limiter: limiter,
+			rl:      rl,
+			cb:      cb,
+		}
+		if err := gw.grpcPass.listen(); err != nil {
+			g.Log().Fatalf(context.Background(), "[gateway] %v", err)
+		}
+	}
+
 	s.Run()
 }
 
 // Shutdown 优雅关闭
 func (gw *Gateway) Shutdown() {
+	if gw.grpcPass != nil {
+		gw.grpcPass.stop(5 * time.Second)
+	}
 	if gw.grpcProxy != nil {
 		gw.grpcProxy.Close()
 	}
+	if gw.certs != nil {
+		gw.certs.Close()
+	}
 	gw.pool.Close()
 	if gw.reg != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,465 @@
+package gateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// httpRule 由 google.api.http 注解编译得到的 REST 路由
+type httpRule struct {
+	httpMethod   string
+	tmpl         *pathTemplate
+	method       *desc.MethodDescriptor
+	body         string // "" 不读取请求体；"*" 整个请求体映射为请求消息；其他为字段名
+	responseBody string // 非空时仅返回响应消息中的该字段
+}
+
+type cachedRules struct {
+	rules    []*httpRule
+	version  uint64 // 构建时 DescriptorRegistry 的版本
+	cachedAt time.Time
+}
+
+// matchHTTPRule 在后端实例的 REST 路由表中查找匹配 httpMethod + path 的规则，返回路径变量
+func (gp *GRPCProxy) matchHTTPRule(ctx context.Context, conn *grpc.ClientConn, address, httpMethod, path string) (*httpRule, map[string]string, bool) {
+	for _, rule := range gp.httpRules(ctx, conn, address) {
+		if rule.httpMethod != httpMethod {
+			continue
+		}
+		if params, ok := rule.tmpl.match(path); ok {
+			return rule, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// httpRules 收集预置描述符和 server reflection 中全部服务的 google.api.http 注解，与方法描述符共用缓存有效期；
+// 同名服务以预置描述符为准，反射不可用时仅使用预置描述符
+func (gp *GRPCProxy) httpRules(ctx context.Context, conn *grpc.ClientConn, address string) []*httpRule {
+	gp.rulesMu.RLock()
+	cached, ok := gp.rules[address]
+	gp.rulesMu.RUnlock()
+
+	ttl := time.Duration(gp.cfg.ReflectionCacheTTLSec) * time.Second
+	version := gp.descriptors.Version()
+	if ok && cached.version == version && time.Since(cached.cachedAt) < ttl {
+		return cached.rules
+	}
+
+	var rules []*httpRule
+	for _, svcDesc := range gp.descriptors.Services() {
+		rules = append(rules, compileHTTPRules(svcDesc)...)
+	}
+
+	refClient := grpcreflect.NewClientAuto(ctx, conn)
+	defer refClient.Reset()
+
+	if services, err := refClient.ListServices(); err != nil {
+		g.Log().Debugf(ctx, "[gateway] reflection list services failed: %s: %v", address, err)
+	} else {
+		for _, name := range services {
+			if gp.descriptors.FindService(name) != nil {
+				continue
+			}
+			svcDesc, err := refClient.ResolveService(name)
+			if err != nil {
+				continue
+			}
+			rules = append(rules, compileHTTPRules(svcDesc)...)
+		}
+	}
+	// 字面量段越多越优先，避免 /v1/users/{id} 抢先匹配 /v1/users/me
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].tmpl.literals() > rules[j].tmpl.literals()
+	})
+
+	gp.rulesMu.Lock()
+	gp.rules[address] = &cachedRules{rules: rules, version: version, cachedAt: time.Now()}
+	gp.rulesMu.Unlock()
+	return rules
+}
+
+// compileHTTPRules 编译服务中所有方法的 google.api.http 注解（含 additional_bindings），非法模板跳过
+func compileHTTPRules(svcDesc *desc.ServiceDescriptor) []*httpRule {
+	var rules []*httpRule
+	for _, md := range svcDesc.GetMethods() {
+		opts := md.GetMethodOptions()
+		if opts == nil || !protov2.HasExtension(opts, annotations.E_Http) {
+			continue
+		}
+		root, ok := protov2.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+		if !ok || root == nil {
+			continue
+		}
+		for _, hr := range append([]*annotations.HttpRule{root}, root.GetAdditionalBindings()...) {
+			httpMethod, pattern := httpRulePattern(hr)
+			if pattern == "" {
+				continue
+			}
+			tmpl, err := parsePathTemplate(pattern)
+			if err != nil {
+				continue
+			}
+			rules = append(rules, &httpRule{
+				httpMethod:   httpMethod,
+				tmpl:         tmpl,
+				method:       md,
+				body:         hr.GetBody(),
+				responseBody: hr.GetResponseBody(),
+			})
+		}
+	}
+	return rules
+}
+
+func httpRulePattern(hr *annotations.HttpRule) (string, string) {
+	switch p := hr.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return "GET", p.Get
+	case *annotations.HttpRule_Put:
+		return "PUT", p.Put
+	case *annotations.HttpRule_Post:
+		return "POST", p.Post
+	case *annotations.HttpRule_Delete:
+		return "DELETE", p.Delete
+	case *annotations.HttpRule_Patch:
+		return "PATCH", p.Patch
+	case *annotations.HttpRule_Custom:
+		return strings.ToUpper(p.Custom.GetKind()), p.Custom.GetPath()
+	}
+	return "", ""
+}
+
+// bind 按规则构造请求消息：先映射请求体，再映射路径变量，body 不为 "*" 时其余字段取自查询参数
+func (rule *httpRule) bind(body []byte, params map[string]string, query url.Values) (*dynamic.Message, error) {
+	msg := dynamic.NewMessage(rule.method.GetInputType())
+
+	switch rule.body {
+	case "":
+	case "*":
+		if len(body) > 0 {
+			if err := msg.UnmarshalJSON(body); err != nil {
+				return nil, fmt.Errorf("invalid JSON body: %w", err)
+			}
+		}
+	default:
+		if len(body) > 0 {
+			wrapped := append([]byte(`{"`+rule.body+`":`), body...)
+			wrapped = append(wrapped, '}')
+			if err := msg.UnmarshalMergeJSON(wrapped); err != nil {
+				return nil, fmt.Errorf("invalid JSON body for field %s: %w", rule.body, err)
+			}
+		}
+	}
+
+	for fieldPath, val := range params {
+		if err := setFieldPath(msg, fieldPath, []string{val}); err != nil {
+			return nil, fmt.Errorf("path param %s: %w", fieldPath, err)
+		}
+	}
+
+	if rule.body == "*" {
+		return msg, nil
+	}
+	for key, vals := range query {
+		if _, ok := params[key]; ok {
+			continue
+		}
+		if rule.body != "" && (key == rule.body || strings.HasPrefix(key, rule.body+".")) {
+			continue
+		}
+		// 未知查询参数忽略，便于客户端附带缓存戳等参数
+		if err := setFieldPath(msg, key, vals); err != nil && err != errUnknownField {
+			return nil, fmt.Errorf("query param %s: %w", key, err)
+		}
+	}
+	return msg, nil
+}
+
+// marshalResponse 将响应消息编码为 JSON，配置 response_body 时仅返回该字段
+func (rule *httpRule) marshalResponse(resp proto.Message) ([]byte, error) {
+	dm, ok := resp.(*dynamic.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T", resp)
+	}
+	if rule == nil || rule.responseBody == "" {
+		return dm.MarshalJSON()
+	}
+	fd := dm.FindFieldDescriptorByName(rule.responseBody)
+	if fd == nil {
+		return nil, fmt.Errorf("response_body field %s not found", rule.responseBody)
+	}
+	data, err := dm.MarshalJSONPB(&jsonpb.Marshaler{EmitDefaults: true})
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.GetJSONName()], nil
+}
+
+var errUnknownField = errors.New("unknown field")
+
+// setFieldPath 按点分字段路径（如 user.id）设置字段值，中间的消息字段按需创建
+func setFieldPath(msg *dynamic.Message, fieldPath string, vals []string) error {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		fd := msg.FindFieldDescriptorByName(name)
+		if fd == nil {
+			fd = msg.FindFieldDescriptorByJSONName(name)
+		}
+		if fd == nil {
+			return errUnknownField
+		}
+
+		if i == len(names)-1 {
+			return setFieldValues(msg, fd, vals)
+		}
+
+		if fd.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || fd.IsRepeated() {
+			return fmt.Errorf("field %s is not a message", fd.GetName())
+		}
+		var sub *dynamic.Message
+		if msg.HasField(fd) {
+			sub, _ = dynamic.AsDynamicMessage(msg.GetField(fd).(proto.Message))
+		}
+		if sub == nil {
+			sub = dynamic.NewMessage(fd.GetMessageType())
+			if err := msg.TrySetField(fd, sub); err != nil {
+				return err
+			}
+		}
+		msg = sub
+	}
+	return nil
+}
+
+func setFieldValues(msg *dynamic.Message, fd *desc.FieldDescriptor, vals []string) error {
+	if fd.IsMap() {
+		return fmt.Errorf("map field %s cannot be bound from URL", fd.GetName())
+	}
+	if !fd.IsRepeated() {
+		v, err := parseFieldValue(fd, vals[len(vals)-1])
+		if err != nil {
+			return err
+		}
+		return msg.TrySetField(fd, v)
+	}
+	for _, s := range vals {
+		v, err := parseFieldValue(fd, s)
+		if err != nil {
+			return err
+		}
+		if err := msg.TryAddRepeatedField(fd, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFieldValue 将字符串转换为标量字段的 Go 类型
+func parseFieldValue(fd *desc.FieldDescriptor, s string) (interface{}, error) {
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return s, nil
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+			return b, nil
+		}
+		return base64.URLEncoding.DecodeString(s)
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return strconv.ParseBool(s)
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_TYPE_SINT32, descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return int32(v), err
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_TYPE_SINT64, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return strconv.ParseInt(s, 10, 64)
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32, descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		v, err := strconv.ParseUint(s, 10, 32)
+		return uint32(v), err
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT64, descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		return strconv.ParseUint(s, 10, 64)
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return strconv.ParseFloat(s, 64)
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		if ev := fd.GetEnumType().FindValueByName(s); ev != nil {
+			return ev.GetNumber(), nil
+		}
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enum value %q for %s", s, fd.GetEnumType().GetFullyQualifiedName())
+		}
+		return int32(v), nil
+	}
+	return nil, fmt.Errorf("field %s of type %s cannot be bound from URL", fd.GetName(), fd.GetType())
+}
+
+// ─── 路径模板 ───
+//
+//	Template = "/" Segments [ ":" Verb ]
+//	Segment  = "*" | "**" | LITERAL | "{" FieldPath [ "=" Segments ] "}"
+
+type segmentKind int
+
+const (
+	segLiteral      segmentKind = iota
+	segWildcard                 // *，匹配一段
+	segDeepWildcard             // **，匹配剩余所有段
+)
+
+type templateSegment struct {
+	kind    segmentKind
+	literal string
+	varIdx  int // 所属变量下标，-1 表示不属于变量
+}
+
+type pathTemplate struct {
+	segments []templateSegment
+	vars     []string
+	verb     string
+}
+
+func parsePathTemplate(pattern string) (*pathTemplate, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("path template must start with /: %s", pattern)
+	}
+	t := &pathTemplate{}
+	rest := pattern[1:]
+
+	// verb 位于最后一个 } 之后的冒号
+	if i := strings.LastIndex(rest, ":"); i >= 0 && i > strings.LastIndex(rest, "}") {
+		t.verb = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	for len(rest) > 0 {
+		var seg string
+		if rest[0] == '{' {
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed variable in %s", pattern)
+			}
+			seg, rest = rest[:end+1], rest[end+1:]
+		} else if i := strings.IndexByte(rest, '/'); i >= 0 {
+			seg, rest = rest[:i], rest[i:]
+		} else {
+			seg, rest = rest, ""
+		}
+		rest = strings.TrimPrefix(rest, "/")
+
+		if strings.HasPrefix(seg, "{") {
+			inner := seg[1 : len(seg)-1]
+			name, sub, hasSub := strings.Cut(inner, "=")
+			if name == "" {
+				return nil, fmt.Errorf("empty variable name in %s", pattern)
+			}
+			idx := len(t.vars)
+			t.vars = append(t.vars, name)
+			if !hasSub {
+				sub = "*"
+			}
+			for _, s := range strings.Split(sub, "/") {
+				t.segments = append(t.segments, newTemplateSegment(s, idx))
+			}
+			continue
+		}
+		t.segments = append(t.segments, newTemplateSegment(seg, -1))
+	}
+
+	for i, seg := range t.segments {
+		if seg.kind == segDeepWildcard && i != len(t.segments)-1 {
+			return nil, fmt.Errorf("** must be the last segment in %s", pattern)
+		}
+	}
+	return t, nil
+}
+
+func newTemplateSegment(s string, varIdx int) templateSegment {
+	switch s {
+	case "*":
+		return templateSegment{kind: segWildcard, varIdx: varIdx}
+	case "**":
+		return templateSegment{kind: segDeepWildcard, varIdx: varIdx}
+	}
+	return templateSegment{kind: segLiteral, literal: s, varIdx: varIdx}
+}
+
+func (t *pathTemplate) literals() int {
+	n := 0
+	for _, seg := range t.segments {
+		if seg.kind == segLiteral {
+			n++
+		}
+	}
+	return n
+}
+
+// match 匹配请求路径，返回变量名 → 值（多段变量以 / 连接）
+func (t *pathTemplate) match(path string) (map[string]string, bool) {
+	path = strings.TrimPrefix(path, "/")
+	if t.verb != "" {
+		var ok bool
+		if path, ok = strings.CutSuffix(path, ":"+t.verb); !ok {
+			return nil, false
+		}
+	}
+	var parts []string
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	captured := make([][]string, len(t.vars))
+	i := 0
+	for _, seg := range t.segments {
+		if seg.kind == segDeepWildcard {
+			if seg.varIdx >= 0 {
+				captured[seg.varIdx] = append(captured[seg.varIdx], parts[i:]...)
+			}
+			i = len(parts)
+			break
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		if seg.kind == segLiteral && parts[i] != seg.literal {
+			return nil, false
+		}
+		if seg.varIdx >= 0 {
+			captured[seg.varIdx] = append(captured[seg.varIdx], parts[i])
+		}
+		i++
+	}
+	if i != len(parts) {
+		return nil, false
+	}
+
+	params := make(map[string]string, len(t.vars))
+	for idx, name := range t.vars {
+		params[name] = strings.Join(captured[idx], "/")
+	}
+	return params, true
+}
@@ -0,0 +1,374 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+	"github.com/krustd/gf-nexus/nexus-gateway/middleware"
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
+)
+
+// rawFrame 未解码的 gRPC 消息
+type rawFrame struct {
+	payload []byte
+}
+
+// rawCodec 原样透传消息字节，不做 protobuf 编解码
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	f, ok := v.(*rawFrame)
+	if !ok {
+		return nil, fmt.Errorf("nexus-gateway: raw codec cannot marshal %T", v)
+	}
+	return f.payload, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	f, ok := v.(*rawFrame)
+	if !ok {
+		return fmt.Errorf("nexus-gateway: raw codec cannot unmarshal into %T", v)
+	}
+	f.payload = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
+
+// 透传时不区分调用类型，一律按双向流处理
+var passthroughDesc = &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+
+// grpcPassthrough 原生 gRPC 透传（gRPC 进 gRPC 出）：
+// 按 metadata 或 :authority 选择服务，复用 HTTP 入口的 IP 过滤、限流、JWT 和熔断策略，原样转发消息帧
+type grpcPassthrough struct {
+	cfg     config.GRPCServerConfig
+	holder  *config.DynamicConfigHolder
+	pool    *ResolverPool
+	conns   *GRPCProxy // 复用转码代理的后端连接
+	tls     *backendTLS
+	outlier *OutlierDetector
+	keyMgr  *middleware.KeyManager
+	ipm     *middleware.IPMatcher
+	limiter *middleware.GlobalRateLimiter
+	rl      *middleware.RateLimitManager
+	cb      *middleware.CircuitBreakerManager
+
+	server *grpc.Server
+}
+
+// listen 创建监听并在后台开始服务
+func (p *grpcPassthrough) listen() error {
+	opts := []grpc.ServerOption{
+		grpc.UnknownServiceHandler(p.handle),
+		grpc.ForceServerCodec(rawCodec{}),
+	}
+	if p.cfg.CertFile != "" && p.cfg.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(p.cfg.CertFile, p.cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("nexus-gateway: load grpc server tls: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	lis, err := net.Listen("tcp", p.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("nexus-gateway: listen grpc %s: %w", p.cfg.Addr, err)
+	}
+	p.server = grpc.NewServer(opts...)
+
+	go func() {
+		if err := p.server.Serve(lis); err != nil {
+			g.Log().Errorf(context.Background(), "[gateway] grpc server stopped: %v", err)
+		}
+	}()
+	g.Log().Infof(context.Background(), "[gateway] grpc passthrough listening on %s", p.cfg.Addr)
+	return nil
+}
+
+// stop 优雅关闭，超时后强制断开仍未结束的流
+func (p *grpcPassthrough) stop(timeout time.Duration) {
+	if p.server == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		p.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		p.server.Stop()
+	}
+}
+
+// handle 处理所有 gRPC 调用
+func (p *grpcPassthrough) handle(_ any, ss grpc.ServerStream) error {
+	start := time.Now()
+	ctx := ss.Context()
+
+	method, ok := grpc.MethodFromServerStream(ss)
+	if !ok {
+		return status.Error(codes.Internal, "missing grpc method")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	clientIP := grpcClientIP(ctx, md)
+	serviceName := p.serviceName(md)
+
+	err := p.serve(ss, md, clientIP, serviceName, method)
+
+	g.Log().Infof(ctx, "[gateway] grpc %s | service=%s | code=%s | latency=%v | ip=%s",
+		method, serviceName, status.Code(err), time.Since(start), clientIP)
+	return err
+}
+
+// serve 按 HTTP 中间件链的顺序执行策略检查后转发
+func (p *grpcPassthrough) serve(ss grpc.ServerStream, md metadata.MD, clientIP, serviceName, method string) error {
+	// 1. IP 黑白名单
+	if ok, reason := p.ipm.Check(clientIP); !ok {
+		return status.Error(codes.PermissionDenied, reason)
+	}
+
+	// 2. 全局限流
+	if !p.limiter.Allow() {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	// 3. JWT 校验
+	claims, err := p.authenticate(md, method)
+	if err != nil {
+		return err
+	}
+
+	if serviceName == "" {
+		return status.Errorf(codes.InvalidArgument, "missing %s metadata", p.cfg.ServiceHeader)
+	}
+
+	// 4. 按规则限流
+	res := p.rl.Check(middleware.RateLimitSubject{
+		ClientIP: clientIP,
+		Header:   func(name string) string { return firstMD(md, name) },
+		Claims:   claims,
+	}, serviceName, "")
+	if !res.Allowed {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded: %s", res.Rule)
+	}
+
+	// 5. 熔断检查
+	if p.cb.Enabled() && !p.cb.Allow(serviceName) {
+		return status.Errorf(codes.Unavailable, "circuit breaker open for %s", serviceName)
+	}
+
+	err = p.forward(ss, md, claims, serviceName, method)
+
+	if p.cb.Enabled() {
+		if backendFailed(err) {
+			p.cb.RecordFailure(serviceName)
+		} else {
+			p.cb.RecordSuccess(serviceName)
+		}
+	}
+	return err
+}
+
+// authenticate 校验 authorization metadata 中的 Bearer token，skip_paths 按完整方法名匹配
+func (p *grpcPassthrough) authenticate(md metadata.MD, method string) (jwt.MapClaims, error) {
+	cfg := p.holder.Load().JWT
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	for _, skip := range cfg.SkipPaths {
+		if skip == method {
+			return nil, nil
+		}
+	}
+
+	tokenStr, ok := strings.CutPrefix(firstMD(md, "authorization"), "Bearer ")
+	if !ok || tokenStr == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+	claims, err := p.keyMgr.Verify(tokenStr)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return claims, nil
+}
+
+// serviceName 优先取 service_header，其次取 :authority 的首段域名（如 user-service.grpc.example.com → user-service）
+func (p *grpcPassthrough) serviceName(md metadata.MD) string {
+	if name := firstMD(md, p.cfg.ServiceHeader); name != "" {
+		return name
+	}
+	host := firstMD(md, ":authority")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	// IP 直连无法推断服务名
+	if host == "" || net.ParseIP(host) != nil {
+		return ""
+	}
+	name, _, _ := strings.Cut(host, ".")
+	return name
+}
+
+// forward 选择实例并在两端之间转发消息帧、header 和 trailer
+func (p *grpcPassthrough) forward(ss grpc.ServerStream, md metadata.MD, claims jwt.MapClaims, serviceName, method string) error {
+	ctx := ss.Context()
+
+	resolver, err := p.pool.GetOrCreate(serviceName)
+	if err != nil {
+		g.Log().Errorf(ctx, "[gateway] resolver create failed: %s: %v", serviceName, err)
+		return status.Errorf(codes.NotFound, "service not found: %s", serviceName)
+	}
+	instance, err := resolver.Resolve()
+	if err != nil {
+		g.Log().Errorf(ctx, "[gateway] resolve failed: %s: %v", serviceName, err)
+		return status.Errorf(codes.Unavailable, "no available instance for %s", serviceName)
+	}
+	if instance.Protocol != registry.ProtocolGRPC {
+		return status.Errorf(codes.Unimplemented, "service %s is not a grpc service", serviceName)
+	}
+
+	conn, err := p.conns.getOrCreateConn(ctx, instance.Address, p.tls.forInstance(serviceName, instance))
+	if err != nil {
+		g.Log().Errorf(ctx, "[gateway] grpc connect failed: %s: %v", instance.Address, err)
+		p.outlier.RecordFailure(serviceName, instance.Address, len(resolver.GetInstances()))
+		return status.Errorf(codes.Unavailable, "grpc connect failed: %s", instance.Address)
+	}
+
+	err = p.pipe(ss, conn, p.outgoingMD(md, claims), method)
+
+	if backendFailed(err) {
+		p.outlier.RecordFailure(serviceName, instance.Address, len(resolver.GetInstances()))
+	} else {
+		p.outlier.RecordSuccess(serviceName, instance.Address)
+	}
+	return err
+}
+
+// outgoingMD 透传客户端 metadata，去掉服务选择头；x-user-id / x-user-role 只取自 JWT，客户端自带的一律丢弃
+func (p *grpcPassthrough) outgoingMD(md metadata.MD, claims jwt.MapClaims) metadata.MD {
+	out := md.Copy()
+	delete(out, ":authority")
+	delete(out, strings.ToLower(p.cfg.ServiceHeader))
+	delete(out, "x-user-id")
+	delete(out, "x-user-role")
+	if userID, ok := claims["user_id"].(string); ok {
+		out.Set("x-user-id", userID)
+	}
+	if role, ok := claims["role"].(string); ok {
+		out.Set("x-user-role", role)
+	}
+	return out
+}
+
+// pipe 双向转发：客户端 → 后端在后台进行，后端 → 客户端结束时以后端状态作为调用结果
+func (p *grpcPassthrough) pipe(ss grpc.ServerStream, conn *grpc.ClientConn, md metadata.MD, method string) error {
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ss.Context(), md))
+	defer cancel()
+
+	cs, err := conn.NewStream(ctx, passthroughDesc, method, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			f := &rawFrame{}
+			if err := ss.RecvMsg(f); err != nil {
+				if err == io.EOF {
+					cs.CloseSend()
+				} else {
+					cancel()
+				}
+				return
+			}
+			// 发送失败表示后端已结束调用，最终状态由 RecvMsg 返回
+			if err := cs.SendMsg(f); err != nil {
+				return
+			}
+		}
+	}()
+
+	headerSent := false
+	for {
+		f := &rawFrame{}
+		err := cs.RecvMsg(f)
+		if err != nil {
+			// 后端未返回任何消息时，header 随 trailer 一并返回
+			if !headerSent {
+				if hdr, herr := cs.Header(); herr == nil {
+					ss.SetHeader(hdr)
+				}
+			}
+			ss.SetTrailer(cs.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if !headerSent {
+			hdr, err := cs.Header()
+			if err != nil {
+				return err
+			}
+			if err := ss.SendHeader(hdr); err != nil {
+				return err
+			}
+			headerSent = true
+		}
+		if err := ss.SendMsg(f); err != nil {
+			return err
+		}
+	}
+}
+
+// backendFailed 判断调用结果是否计入熔断 / 异常检测（客户端取消和业务错误不计入）
+func backendFailed(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// grpcClientIP 与 HTTP 入口一致，优先取 X-Forwarded-For / X-Real-IP，其次取对端地址
+func grpcClientIP(ctx context.Context, md metadata.MD) string {
+	if xff := firstMD(md, "x-forwarded-for"); xff != "" {
+		ip, _, _ := strings.Cut(xff, ",")
+		return strings.TrimSpace(ip)
+	}
+	if ip := firstMD(md, "x-real-ip"); ip != "" {
+		return ip
+	}
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		return pr.Addr.String()
+	}
+	return host
+}
+
+func firstMD(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
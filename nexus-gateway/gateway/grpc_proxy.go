@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"strings"
@@ -10,12 +11,14 @@ import (
 
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gorilla/websocket"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/dynamic"
 	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -23,15 +26,22 @@ import (
 	"github.com/krustd/gf-nexus/nexus-gateway/config"
 )
 
-// GRPCProxy 处理 HTTP→gRPC 转码（仅 Unary）
+// GRPCProxy 处理 HTTP→gRPC 转码（Unary + Server Streaming，客户端流 / 双向流走 WebSocket）
+// 支持 Service/Method 路径和 google.api.http 注解定义的 REST 路径两种调用方式；
+// 方法描述符优先取自预置的 DescriptorRegistry，未注册的服务通过 server reflection 获取
 type GRPCProxy struct {
-	cfg config.GRPCConfig
+	cfg         config.GRPCConfig
+	descriptors *DescriptorRegistry
+	upgrader    *websocket.Upgrader
 
 	connMu sync.RWMutex
 	conns  map[string]*grpc.ClientConn
 
 	cacheMu sync.RWMutex
 	cache   map[string]*cachedDescriptor
+
+	rulesMu sync.RWMutex
+	rules   map[string]*cachedRules // 实例地址 → REST 路由表
 }
 
 type cachedDescriptor struct {
@@ -39,17 +49,29 @@ type cachedDescriptor struct {
 	cachedAt time.Time
 }
 
-func NewGRPCProxy(cfg config.GRPCConfig) *GRPCProxy {
+// NewGRPCProxy descriptors 为空时仅使用 server reflection
+func NewGRPCProxy(cfg config.GRPCConfig, descriptors *DescriptorRegistry, holder *config.DynamicConfigHolder) *GRPCProxy {
 	return &GRPCProxy{
-		cfg:   cfg,
-		conns: make(map[string]*grpc.ClientConn),
-		cache: make(map[string]*cachedDescriptor),
+		cfg:         cfg,
+		descriptors: descriptors,
+		upgrader:    newWSUpgrader(holder),
+		conns:       make(map[string]*grpc.ClientConn),
+		cache:       make(map[string]*cachedDescriptor),
+		rules:       make(map[string]*cachedRules),
 	}
 }
 
-func (gp *GRPCProxy) getOrCreateConn(ctx context.Context, address string) (*grpc.ClientConn, error) {
+// getOrCreateConn tlsCfg 为空时使用明文连接；同一地址的明文与 TLS 连接分别缓存
+func (gp *GRPCProxy) getOrCreateConn(ctx context.Context, address string, tlsCfg *tls.Config) (*grpc.ClientConn, error) {
+	key := address
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		key = fmt.Sprintf("tls|%p|%s", tlsCfg, address)
+		creds = credentials.NewTLS(tlsCfg)
+	}
+
 	gp.connMu.RLock()
-	conn, ok := gp.conns[address]
+	conn, ok := gp.conns[key]
 	gp.connMu.RUnlock()
 	if ok {
 		return conn, nil
@@ -58,7 +80,7 @@ func (gp *GRPCProxy) getOrCreateConn(ctx context.Context, address string) (*grpc
 	gp.connMu.Lock()
 	defer gp.connMu.Unlock()
 
-	if conn, ok := gp.conns[address]; ok {
+	if conn, ok := gp.conns[key]; ok {
 		return conn, nil
 	}
 
@@ -66,13 +88,13 @@ func (gp *GRPCProxy) getOrCreateConn(ctx context.Context, address string) (*grpc
 	defer cancel()
 
 	conn, err := grpc.DialContext(dialCtx, address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("grpc dial %s: %w", address, err)
 	}
-	gp.conns[address] = conn
+	gp.conns[key] = conn
 	return conn, nil
 }
 
@@ -84,6 +106,15 @@ func (gp *GRPCProxy) resolveMethod(ctx context.Context, conn *grpc.ClientConn, a
 	fullServiceName := fullMethod[:slashIdx]
 	methodName := fullMethod[slashIdx+1:]
 
+	// 预置描述符优先
+	if svcDesc := gp.descriptors.FindService(fullServiceName); svcDesc != nil {
+		md := svcDesc.FindMethodByName(methodName)
+		if md == nil {
+			return nil, fmt.Errorf("method %s not found in service %s", methodName, fullServiceName)
+		}
+		return md, nil
+	}
+
 	cacheKey := address + "|" + fullServiceName
 	gp.cacheMu.RLock()
 	cached, ok := gp.cache[cacheKey]
@@ -118,54 +149,73 @@ func (gp *GRPCProxy) resolveMethod(ctx context.Context, conn *grpc.ClientConn, a
 }
 
 // Handle 处理 HTTP→gRPC 转码
-func (gp *GRPCProxy) Handle(r *ghttp.Request, address, method string) {
+func (gp *GRPCProxy) Handle(r *ghttp.Request, address string, tlsCfg *tls.Config, method string) {
 	ctx := r.GetCtx()
 
 	// 1. 获取/创建 gRPC 连接
-	conn, err := gp.getOrCreateConn(ctx, address)
+	conn, err := gp.getOrCreateConn(ctx, address, tlsCfg)
 	if err != nil {
 		g.Log().Errorf(ctx, "[gateway] grpc connect failed: %s: %v", address, err)
 		GatewayError(r, CodeBackendError, fmt.Sprintf("grpc connect failed: %s", address))
 		return
 	}
 
-	// 2. 通过反射解析方法描述符
-	md, err := gp.resolveMethod(ctx, conn, address, method)
-	if err != nil {
-		g.Log().Errorf(ctx, "[gateway] grpc resolve method failed: %s: %v", method, err)
-		GatewayError(r, CodeBackendError, fmt.Sprintf("grpc method not found: %s", method))
-		return
+	// 2. 优先按 google.api.http 注解匹配 REST 路径，未命中时按 Service/Method 通过反射解析
+	rule, params, ok := gp.matchHTTPRule(ctx, conn, address, r.Method, "/"+method)
+	var md *desc.MethodDescriptor
+	if ok {
+		md = rule.method
+	} else {
+		md, err = gp.resolveMethod(ctx, conn, address, method)
+		if err != nil {
+			g.Log().Errorf(ctx, "[gateway] grpc resolve method failed: %s: %v", method, err)
+			GatewayError(r, CodeBackendError, fmt.Sprintf("grpc method not found: %s", method))
+			return
+		}
 	}
 
-	// 3. 拒绝 streaming 方法
-	if md.IsClientStreaming() || md.IsServerStreaming() {
-		GatewayError(r, CodeBackendError, fmt.Sprintf("streaming not supported: %s", method))
+	// 3. 客户端流 / 双向流仅支持 WebSocket
+	if websocket.IsWebSocketUpgrade(r.Request) {
+		gp.handleWebSocket(r, conn, md, outgoingContext(ctx, r))
+		return
+	}
+	if md.IsClientStreaming() {
+		GatewayError(r, CodeBackendError, fmt.Sprintf("client streaming requires websocket: %s", method))
 		return
 	}
 
-	// 4. 读取请求体，JSON → protobuf
+	// 4. 读取请求体，JSON → protobuf（REST 路径同时绑定路径变量和查询参数）
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		GatewayError(r, CodeBackendError, "failed to read request body")
 		return
 	}
 
-	reqMsg := dynamic.NewMessage(md.GetInputType())
-	if len(body) > 0 {
-		if err := reqMsg.UnmarshalJSON(body); err != nil {
-			GatewayError(r, CodeBackendError, fmt.Sprintf("invalid JSON for %s: %v", method, err))
+	var reqMsg *dynamic.Message
+	if rule != nil {
+		reqMsg, err = rule.bind(body, params, r.URL.Query())
+		if err != nil {
+			GatewayError(r, CodeBackendError, fmt.Sprintf("invalid request for %s: %v", md.GetFullyQualifiedName(), err))
 			return
 		}
+	} else {
+		reqMsg = dynamic.NewMessage(md.GetInputType())
+		if len(body) > 0 {
+			if err := reqMsg.UnmarshalJSON(body); err != nil {
+				GatewayError(r, CodeBackendError, fmt.Sprintf("invalid JSON for %s: %v", method, err))
+				return
+			}
+		}
 	}
 
 	// 5. 转发关键 HTTP 头为 gRPC metadata
-	outMD := metadata.MD{}
-	for _, key := range []string{"Authorization", "X-Request-Id", "X-Trace-Id", "X-User-Id", "X-User-Role"} {
-		if val := r.Header.Get(key); val != "" {
-			outMD.Set(strings.ToLower(key), val)
-		}
+	callCtx := outgoingContext(ctx, r)
+
+	// 服务端流：以 SSE / NDJSON 逐条推送，不受 RequestTimeoutMs 限制
+	if md.IsServerStreaming() {
+		gp.handleServerStream(r, conn, md, rule, reqMsg, callCtx)
+		return
 	}
-	callCtx := metadata.NewOutgoingContext(ctx, outMD)
 
 	// 6. 设置调用超时
 	callCtx, cancel := context.WithTimeout(callCtx, time.Duration(gp.cfg.RequestTimeoutMs)*time.Millisecond)
@@ -175,22 +225,12 @@ func (gp *GRPCProxy) Handle(r *ghttp.Request, address, method string) {
 	stub := grpcdynamic.NewStub(conn)
 	respMsg, err := stub.InvokeRpc(callCtx, md, reqMsg)
 	if err != nil {
-		st, ok := status.FromError(err)
-		if ok {
-			httpStatus := grpcCodeToHTTP(st.Code())
-			r.Response.WriteStatus(httpStatus)
-			r.Response.WriteJsonExit(g.Map{
-				"code":    int(st.Code()),
-				"message": st.Message(),
-			})
-		} else {
-			GatewayError(r, CodeBackendError, fmt.Sprintf("grpc call failed: %v", err))
-		}
+		writeGRPCError(r, err)
 		return
 	}
 
 	// 8. protobuf → JSON 响应
-	respJSON, err := respMsg.(*dynamic.Message).MarshalJSON()
+	respJSON, err := rule.marshalResponse(respMsg)
 	if err != nil {
 		GatewayError(r, CodeBackendError, fmt.Sprintf("marshal response failed: %v", err))
 		return
@@ -201,6 +241,32 @@ func (gp *GRPCProxy) Handle(r *ghttp.Request, address, method string) {
 	r.Response.Write(respJSON)
 }
 
+// outgoingContext 转发关键 HTTP 头为 gRPC metadata
+func outgoingContext(ctx context.Context, r *ghttp.Request) context.Context {
+	outMD := metadata.MD{}
+	for _, key := range []string{"Authorization", "X-Request-Id", "X-Trace-Id", "X-User-Id", "X-User-Role"} {
+		if val := r.Header.Get(key); val != "" {
+			outMD.Set(strings.ToLower(key), val)
+		}
+	}
+	return metadata.NewOutgoingContext(ctx, outMD)
+}
+
+// writeGRPCError 将 gRPC 错误写为 HTTP 错误响应
+func writeGRPCError(r *ghttp.Request, err error) {
+	st, ok := status.FromError(err)
+	if ok {
+		httpStatus := grpcCodeToHTTP(st.Code())
+		r.Response.WriteStatus(httpStatus)
+		r.Response.WriteJsonExit(g.Map{
+			"code":    int(st.Code()),
+			"message": st.Message(),
+		})
+	} else {
+		GatewayError(r, CodeBackendError, fmt.Sprintf("grpc call failed: %v", err))
+	}
+}
+
 // grpcCodeToHTTP 将 gRPC 状态码映射为 HTTP 状态码
 func grpcCodeToHTTP(code codes.Code) int {
 	switch code {
@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 服务端流的 HTTP 输出格式，按 Accept 选择
+const (
+	contentTypeSSE    = "text/event-stream"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// streamTrailer 流结束时的最终 gRPC 状态
+type streamTrailer struct {
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	HTTPStatus int    `json:"http_status"`
+}
+
+// streamWriter 逐条写出流消息：
+//
+//	SSE:    event: message\ndata: {...}\n\n，结束时 event: trailer
+//	NDJSON: {"result": {...}}\n，结束时 {"trailer": {...}}\n
+type streamWriter struct {
+	r   *ghttp.Request
+	sse bool
+}
+
+func newStreamWriter(r *ghttp.Request) *streamWriter {
+	return &streamWriter{
+		r:   r,
+		sse: strings.Contains(r.Header.Get("Accept"), contentTypeSSE),
+	}
+}
+
+// start 写出响应头，之后状态码不可再修改
+func (w *streamWriter) start() {
+	h := w.r.Response.Header()
+	if w.sse {
+		h.Set("Content-Type", contentTypeSSE)
+	} else {
+		h.Set("Content-Type", contentTypeNDJSON)
+	}
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // 禁止 nginx 缓冲
+	w.r.Response.WriteHeader(200)
+}
+
+func (w *streamWriter) write(event, key string, data []byte) error {
+	if w.sse {
+		w.r.Response.Writef("event: %s\ndata: %s\n\n", event, data)
+	} else {
+		w.r.Response.Writef("{%q:%s}\n", key, data)
+	}
+	w.r.Response.Flush()
+	return w.r.Context().Err()
+}
+
+func (w *streamWriter) trailer(err error) {
+	w.write("trailer", "trailer", trailerJSON(err))
+}
+
+// trailerJSON 将最终 gRPC 状态（nil 表示 OK）编码为 JSON
+func trailerJSON(err error) []byte {
+	st := status.Convert(err)
+	data, _ := json.Marshal(streamTrailer{
+		Code:       int(st.Code()),
+		Message:    st.Message(),
+		HTTPStatus: grpcCodeToHTTP(st.Code()),
+	})
+	return data
+}
+
+// handleServerStream 调用服务端流 RPC，收到一条消息即推送一条；
+// HTTP 客户端断开时取消 gRPC 调用，最终状态以 trailer 事件返回
+func (gp *GRPCProxy) handleServerStream(r *ghttp.Request, conn *grpc.ClientConn, md *desc.MethodDescriptor, rule *httpRule, reqMsg *dynamic.Message, callCtx context.Context) {
+	ctx := r.GetCtx()
+	callCtx, cancel := context.WithCancel(callCtx)
+	defer cancel()
+
+	stub := grpcdynamic.NewStub(conn)
+	stream, err := stub.InvokeRpcServerStream(callCtx, md, reqMsg)
+	if err != nil {
+		writeGRPCError(r, err)
+		return
+	}
+
+	w := newStreamWriter(r)
+	started := false
+	for {
+		resp, err := stream.RecvMsg()
+		if err == io.EOF {
+			err = nil
+		}
+		if err != nil || resp == nil {
+			// 尚未推送任何消息时按普通错误响应返回，便于客户端按 HTTP 状态码处理
+			if !started && err != nil {
+				writeGRPCError(r, err)
+				return
+			}
+			if !started {
+				w.start()
+			}
+			w.trailer(err)
+			return
+		}
+
+		if !started {
+			w.start()
+			started = true
+		}
+		data, err := rule.marshalResponse(resp)
+		if err != nil {
+			w.trailer(status.Errorf(codes.Internal, "marshal response failed: %v", err))
+			return
+		}
+		// 客户端已断开：返回后 cancel 终止 gRPC 调用
+		if err := w.write("message", "result", data); err != nil {
+			g.Log().Debugf(ctx, "[gateway] grpc stream %s closed by client: %v", md.GetFullyQualifiedName(), err)
+			return
+		}
+	}
+}
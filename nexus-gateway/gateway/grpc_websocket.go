@@ -0,0 +1,196 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+	"github.com/krustd/gf-nexus/nexus-gateway/middleware"
+)
+
+const wsReadLimit = 4 << 20 // 与 gRPC 默认最大消息大小一致
+
+// newWSUpgrader 浏览器 WebSocket 不受 CORS 约束，握手时按动态配置校验 Origin：
+// 开启 CORS 时须在 allow_origins 中，否则仅允许同源；不带 Origin 的非浏览器客户端不受限制
+func newWSUpgrader(holder *config.DynamicConfigHolder) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		Subprotocols: []string{middleware.WSTokenProtocol},
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			if cors := holder.Load().CORS; cors.Enabled {
+				return middleware.OriginAllowed(cors, origin)
+			}
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		},
+	}
+}
+
+// wsCall 客户端流 / 双向流的统一抽象
+type wsCall interface {
+	SendMsg(m proto.Message) error
+	CloseSend() error
+	RecvMsg() (proto.Message, error)
+}
+
+// clientStreamCall 将客户端流适配为 wsCall：CloseSend 后 RecvMsg 返回唯一响应，之后返回 io.EOF
+type clientStreamCall struct {
+	ctx    context.Context
+	cs     *grpcdynamic.ClientStream
+	closed chan struct{}
+	once   sync.Once
+	done   bool
+}
+
+func (c *clientStreamCall) SendMsg(m proto.Message) error { return c.cs.SendMsg(m) }
+
+func (c *clientStreamCall) CloseSend() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *clientStreamCall) RecvMsg() (proto.Message, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	select {
+	case <-c.closed:
+	case <-c.ctx.Done():
+		return nil, status.FromContextError(c.ctx.Err()).Err()
+	}
+	c.done = true
+	return c.cs.CloseAndReceive()
+}
+
+// handleWebSocket 通过 WebSocket 承载客户端流 / 双向流 RPC：
+//
+//	客户端 → 网关：每个 JSON 文本帧为一条请求消息，空文本帧表示半关闭（CloseSend）
+//	网关 → 客户端：每条响应消息为 {"result": {...}}，结束时发送 {"trailer": {...}} 后关闭连接
+func (gp *GRPCProxy) handleWebSocket(r *ghttp.Request, conn *grpc.ClientConn, md *desc.MethodDescriptor, callCtx context.Context) {
+	ctx := r.GetCtx()
+	if !md.IsClientStreaming() {
+		GatewayError(r, CodeBackendError, fmt.Sprintf("websocket only supports client/bidi streaming: %s", md.GetFullyQualifiedName()))
+		return
+	}
+
+	ws, err := gp.upgrader.Upgrade(r.Response.RawWriter(), r.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已写出 HTTP 错误响应
+		g.Log().Warningf(ctx, "[gateway] websocket upgrade failed: %v", err)
+		return
+	}
+	defer ws.Close()
+	ws.SetReadLimit(wsReadLimit)
+
+	callCtx, cancel := context.WithCancel(callCtx)
+	defer cancel()
+
+	call, err := openWSCall(callCtx, conn, md)
+	if err != nil {
+		writeWSTrailer(ws, err)
+		return
+	}
+
+	// 读循环：WebSocket 帧 → 请求消息；客户端断开或帧非法时取消调用
+	var abortErr error
+	var abortMu sync.Mutex
+	abort := func(err error) {
+		abortMu.Lock()
+		if abortErr == nil {
+			abortErr = err
+		}
+		abortMu.Unlock()
+		cancel()
+	}
+	go func() {
+		for {
+			typ, data, err := ws.ReadMessage()
+			if err != nil {
+				abort(status.Error(codes.Canceled, "websocket closed by client"))
+				return
+			}
+			if typ != websocket.TextMessage {
+				abort(status.Error(codes.InvalidArgument, "only text frames are supported"))
+				return
+			}
+			if len(data) == 0 {
+				call.CloseSend()
+				return
+			}
+			reqMsg := dynamic.NewMessage(md.GetInputType())
+			if err := reqMsg.UnmarshalJSON(data); err != nil {
+				abort(status.Errorf(codes.InvalidArgument, "invalid JSON for %s: %v", md.GetFullyQualifiedName(), err))
+				return
+			}
+			// 发送失败（io.EOF）表示服务端已结束调用，最终状态由 RecvMsg 返回
+			if err := call.SendMsg(reqMsg); err != nil {
+				call.CloseSend()
+				return
+			}
+		}
+	}()
+
+	// 写循环：响应消息 → WebSocket 帧
+	for {
+		resp, err := call.RecvMsg()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			abortMu.Lock()
+			if abortErr != nil {
+				err = abortErr
+			}
+			abortMu.Unlock()
+			writeWSTrailer(ws, err)
+			return
+		}
+
+		data, err := resp.(*dynamic.Message).MarshalJSON()
+		if err != nil {
+			writeWSTrailer(ws, status.Errorf(codes.Internal, "marshal response failed: %v", err))
+			return
+		}
+		if err := ws.WriteMessage(websocket.TextMessage, []byte(`{"result":`+string(data)+`}`)); err != nil {
+			g.Log().Debugf(ctx, "[gateway] websocket write failed: %v", err)
+			return
+		}
+	}
+}
+
+func openWSCall(ctx context.Context, conn *grpc.ClientConn, md *desc.MethodDescriptor) (wsCall, error) {
+	stub := grpcdynamic.NewStub(conn)
+	if md.IsServerStreaming() {
+		return stub.InvokeRpcBidiStream(ctx, md)
+	}
+	cs, err := stub.InvokeRpcClientStream(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+	return &clientStreamCall{ctx: ctx, cs: cs, closed: make(chan struct{})}, nil
+}
+
+// writeWSTrailer 发送最终状态帧并正常关闭连接
+func writeWSTrailer(ws *websocket.Conn, err error) {
+	ws.WriteMessage(websocket.TextMessage, []byte(`{"trailer":`+string(trailerJSON(err))+`}`))
+	ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
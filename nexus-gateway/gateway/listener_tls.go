@@ -0,0 +1,299 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+)
+
+// CertStore 网关 HTTPS 监听的证书集合：按 SNI 选择证书，文件变更或配置中心发布新证书时热更新，无需重启
+type CertStore struct {
+	cfg config.ListenerTLSConfig
+
+	mu        sync.Mutex
+	files     []*fileCert
+	clientCA  *fileStamp
+	published map[string]tls.Certificate // 来源（配置键）→ 配置中心下发的证书
+
+	current atomic.Pointer[tls.Config]
+	stopCh  chan struct{}
+	once    sync.Once
+}
+
+type fileStamp struct {
+	path    string
+	modTime time.Time
+}
+
+type fileCert struct {
+	cert, key fileStamp
+	tlsCert   tls.Certificate
+}
+
+// NewCertStore 加载配置中的全部证书文件，任一证书不可用时返回错误
+func NewCertStore(cfg config.ListenerTLSConfig) (*CertStore, error) {
+	cs := &CertStore{
+		cfg:       cfg,
+		published: make(map[string]tls.Certificate),
+		stopCh:    make(chan struct{}),
+	}
+	for _, c := range cfg.Certificates {
+		fc := &fileCert{cert: fileStamp{path: c.CertFile}, key: fileStamp{path: c.KeyFile}}
+		if err := fc.load(); err != nil {
+			return nil, err
+		}
+		cs.files = append(cs.files, fc)
+	}
+	if cfg.ClientCAFile != "" {
+		cs.clientCA = &fileStamp{path: cfg.ClientCAFile}
+	}
+	if _, err := parseClientAuth(cfg.ClientAuth, cs.clientCA != nil); err != nil {
+		return nil, err
+	}
+	// 仅使用配置中心证书时，等待首次下发
+	if len(cs.files) == 0 && len(cfg.CertConfigKeys) > 0 {
+		return cs, nil
+	}
+	if err := cs.rebuild(); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+func (fc *fileCert) load() error {
+	cert, err := tls.LoadX509KeyPair(fc.cert.path, fc.key.path)
+	if err != nil {
+		return fmt.Errorf("nexus-gateway: load certificate %s: %w", fc.cert.path, err)
+	}
+	fc.tlsCert = cert
+	fc.cert.modTime = modTime(fc.cert.path)
+	fc.key.modTime = modTime(fc.key.path)
+	return nil
+}
+
+func (fc *fileCert) changed() bool {
+	return !modTime(fc.cert.path).Equal(fc.cert.modTime) || !modTime(fc.key.path).Equal(fc.key.modTime)
+}
+
+func modTime(path string) time.Time {
+	if fi, err := os.Stat(path); err == nil {
+		return fi.ModTime()
+	}
+	return time.Time{}
+}
+
+// rebuild 以当前证书生成新的 TLS 设置，之后的握手立即生效
+func (cs *CertStore) rebuild() error {
+	certs := make([]tls.Certificate, 0, len(cs.files)+len(cs.published))
+	for _, fc := range cs.files {
+		certs = append(certs, fc.tlsCert)
+	}
+	sources := make([]string, 0, len(cs.published))
+	for source := range cs.published {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		certs = append(certs, cs.published[source])
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("nexus-gateway: no server certificate configured")
+	}
+
+	tc := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: certs, // 多证书时按 SNI 选择匹配的证书，无匹配时使用第一张
+		NextProtos:   []string{"http/1.1"},
+	}
+
+	clientAuth, err := parseClientAuth(cs.cfg.ClientAuth, cs.clientCA != nil)
+	if err != nil {
+		return err
+	}
+	tc.ClientAuth = clientAuth
+	if cs.clientCA != nil {
+		pemData, err := os.ReadFile(cs.clientCA.path)
+		if err != nil {
+			return fmt.Errorf("nexus-gateway: read client ca %s: %w", cs.clientCA.path, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return fmt.Errorf("nexus-gateway: no certificate found in %s", cs.clientCA.path)
+		}
+		tc.ClientCAs = pool
+		cs.clientCA.modTime = modTime(cs.clientCA.path)
+	}
+
+	cs.current.Store(tc)
+	return nil
+}
+
+func parseClientAuth(mode string, hasCA bool) (tls.ClientAuthType, error) {
+	switch mode {
+	case "":
+		if hasCA {
+			return tls.RequireAndVerifyClientCert, nil
+		}
+		return tls.NoClientCert, nil
+	case "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("nexus-gateway: unknown client_auth %q", mode)
+}
+
+// ServerConfig 返回交给 HTTP 服务的 TLS 设置，每次握手读取最新证书；尚无可用证书时返回 nil
+func (cs *CertStore) ServerConfig() *tls.Config {
+	base := cs.current.Load()
+	if base == nil {
+		return nil
+	}
+	return &tls.Config{
+		MinVersion:   base.MinVersion,
+		Certificates: base.Certificates,
+		NextProtos:   base.NextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return cs.current.Load(), nil
+		},
+	}
+}
+
+// Publish 设置 source 提供的证书（PEM 格式，证书链与私钥放在同一份内容中）
+func (cs *CertStore) Publish(source string, pemData []byte) error {
+	var certPEM, keyPEM []byte
+	for rest := pemData; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			keyPEM = append(keyPEM, pem.EncodeToMemory(block)...)
+		} else {
+			certPEM = append(certPEM, pem.EncodeToMemory(block)...)
+		}
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("nexus-gateway: parse certificate %s: %w", source, err)
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	old, existed := cs.published[source]
+	cs.published[source] = cert
+	if err := cs.rebuild(); err != nil {
+		if existed {
+			cs.published[source] = old
+		} else {
+			delete(cs.published, source)
+		}
+		return err
+	}
+	return nil
+}
+
+// Remove 移除 source 提供的证书，移除后没有任何证书时保留原证书
+func (cs *CertStore) Remove(source string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	old, ok := cs.published[source]
+	if !ok {
+		return nil
+	}
+	delete(cs.published, source)
+	if err := cs.rebuild(); err != nil {
+		cs.published[source] = old
+		return err
+	}
+	return nil
+}
+
+// Reload 重新加载发生变更的证书文件，加载失败的证书保留旧版本
+func (cs *CertStore) Reload() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	changed := cs.clientCA != nil && !modTime(cs.clientCA.path).Equal(cs.clientCA.modTime)
+	var errs []string
+	for _, fc := range cs.files {
+		if !fc.changed() {
+			continue
+		}
+		if err := fc.load(); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		changed = true
+	}
+	if changed {
+		if err := cs.rebuild(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// watch 周期检查证书文件变更，直到 Close
+func (cs *CertStore) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cs.stopCh:
+			return
+		case <-ticker.C:
+			if err := cs.Reload(); err != nil {
+				g.Log().Warningf(context.Background(), "[gateway] reload certificate: %v", err)
+			}
+		}
+	}
+}
+
+// Close 停止证书文件检查
+func (cs *CertStore) Close() {
+	cs.once.Do(func() { close(cs.stopCh) })
+}
+
+// httpsRedirect 将明文 HTTP 请求 301 重定向到 HTTPS，/health 除外（供负载均衡探活）
+func httpsRedirect(httpsAddr string) ghttp.HandlerFunc {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+	return func(r *ghttp.Request) {
+		if r.TLS != nil || r.URL.Path == "/health" {
+			r.Middleware.Next()
+			return
+		}
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		r.Response.RedirectTo("https://"+host+r.RequestURI, 301)
+	}
+}
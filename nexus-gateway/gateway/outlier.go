@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+	"github.com/krustd/gf-nexus/nexus-gateway/metrics"
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
+)
+
+// hostState 单个实例地址的异常统计
+type hostState struct {
+	failures     int       // 连续失败次数
+	ejections    int       // 驱逐倍数，决定下次驱逐时长
+	ejectedUntil time.Time // 零值表示未驱逐
+}
+
+func (h *hostState) ejected(now time.Time) bool {
+	return now.Before(h.ejectedUntil)
+}
+
+// OutlierDetector 被动异常实例检测（类 Envoy outlier detection）
+// 根据真实请求结果统计每个实例的连续失败次数，超过阈值后将其从 Resolver 候选集中临时剔除
+type OutlierDetector struct {
+	holder *config.DynamicConfigHolder
+
+	mu    sync.Mutex
+	hosts map[string]map[string]*hostState // service → address → state
+}
+
+func NewOutlierDetector(holder *config.DynamicConfigHolder) *OutlierDetector {
+	return &OutlierDetector{
+		holder: holder,
+		hosts:  make(map[string]map[string]*hostState),
+	}
+}
+
+func (od *OutlierDetector) cfg() config.OutlierConfig {
+	return od.holder.Load().Outlier
+}
+
+// Enabled 返回异常检测是否启用
+func (od *OutlierDetector) Enabled() bool {
+	return od.cfg().Enabled
+}
+
+func (od *OutlierDetector) hostLocked(serviceName, address string) *hostState {
+	svc, ok := od.hosts[serviceName]
+	if !ok {
+		svc = make(map[string]*hostState)
+		od.hosts[serviceName] = svc
+	}
+	h, ok := svc[address]
+	if !ok {
+		h = &hostState{}
+		svc[address] = h
+	}
+	return h
+}
+
+// RecordSuccess 记录成功请求，清零连续失败计数
+func (od *OutlierDetector) RecordSuccess(serviceName, address string) {
+	if !od.Enabled() {
+		return
+	}
+	od.mu.Lock()
+	defer od.mu.Unlock()
+	od.hostLocked(serviceName, address).failures = 0
+}
+
+// RecordFailure 记录失败请求（连接错误 / 5xx），total 为服务当前实例总数，用于计算驱逐比例上限
+func (od *OutlierDetector) RecordFailure(serviceName, address string, total int) {
+	cfg := od.cfg()
+	if !cfg.Enabled {
+		return
+	}
+
+	od.mu.Lock()
+	defer od.mu.Unlock()
+
+	now := time.Now()
+	h := od.hostLocked(serviceName, address)
+	if h.ejected(now) {
+		return
+	}
+
+	h.failures++
+	if h.failures < cfg.ConsecutiveErrors {
+		return
+	}
+
+	// 驱逐比例上限（至少允许驱逐一个实例）
+	ejected := 0
+	for _, other := range od.hosts[serviceName] {
+		if other.ejected(now) {
+			ejected++
+		}
+	}
+	maxEjected := total * cfg.MaxEjectionPercent / 100
+	if maxEjected < 1 {
+		maxEjected = 1
+	}
+	if ejected >= maxEjected {
+		return
+	}
+
+	// 驱逐倍数随未驱逐时长衰减：每健康一个 base 周期减一
+	base := time.Duration(cfg.BaseEjectionSec) * time.Second
+	if !h.ejectedUntil.IsZero() && h.ejections > 0 {
+		decay := int(now.Sub(h.ejectedUntil) / base)
+		h.ejections -= decay
+		if h.ejections < 0 {
+			h.ejections = 0
+		}
+	}
+	h.ejections++
+
+	duration := base << uint(h.ejections-1)
+	maxDuration := time.Duration(cfg.MaxEjectionSec) * time.Second
+	if duration > maxDuration || duration <= 0 {
+		duration = maxDuration
+	}
+
+	h.failures = 0
+	h.ejectedUntil = now.Add(duration)
+	metrics.OutlierEjections.WithLabelValues(serviceName).Inc()
+	log.Printf("[nexus-gateway] outlier ejected: %s %s for %v (ejections=%d)", serviceName, address, duration, h.ejections)
+}
+
+// Filter 实现 registry.InstanceFilter，剔除处于驱逐期内的实例；若全部被驱逐则原样返回
+func (od *OutlierDetector) Filter(serviceName string, instances []*registry.ServiceInstance) []*registry.ServiceInstance {
+	if !od.Enabled() {
+		return instances
+	}
+
+	od.mu.Lock()
+	svc := od.hosts[serviceName]
+	if len(svc) == 0 {
+		od.mu.Unlock()
+		return instances
+	}
+	now := time.Now()
+	present := make(map[string]bool, len(instances))
+	kept := make([]*registry.ServiceInstance, 0, len(instances))
+	for _, inst := range instances {
+		present[inst.Address] = true
+		if h, ok := svc[inst.Address]; ok && h.ejected(now) {
+			continue
+		}
+		kept = append(kept, inst)
+	}
+	// 清理已下线且不在驱逐期内的实例状态
+	for addr, h := range svc {
+		if !present[addr] && !h.ejected(now) {
+			delete(svc, addr)
+		}
+	}
+	od.mu.Unlock()
+
+	if len(kept) == 0 {
+		return instances
+	}
+	return kept
+}
@@ -1,11 +1,15 @@
 package gateway
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gogf/gf/v2/frame/g"
@@ -18,35 +22,71 @@ import (
 // ProxyHandler 泛化调用反向代理
 type ProxyHandler struct {
 	pool       *ResolverPool
+	timeout    config.TimeoutConfig
 	httpClient *http.Client
 	grpcProxy  *GRPCProxy
+	outlier    *OutlierDetector
+	holder     *config.DynamicConfigHolder
+	tls        *backendTLS
+
+	tlsMu      sync.Mutex
+	tlsClients map[*tls.Config]*http.Client // 每个 TLS 设置独立的连接池
+}
+
+// NewProxyHandler backendTLS 为空时后端一律使用明文
+func NewProxyHandler(pool *ResolverPool, cfg config.TimeoutConfig, holder *config.DynamicConfigHolder, grpcProxy *GRPCProxy, outlier *OutlierDetector, backendTLS *backendTLS) *ProxyHandler {
+	return &ProxyHandler{
+		pool:       pool,
+		timeout:    cfg,
+		httpClient: newHTTPClient(cfg, nil),
+		grpcProxy:  grpcProxy,
+		outlier:    outlier,
+		holder:     holder,
+		tls:        backendTLS,
+		tlsClients: make(map[*tls.Config]*http.Client),
+	}
 }
 
-func NewProxyHandler(pool *ResolverPool, cfg config.TimeoutConfig, grpcProxy *GRPCProxy) *ProxyHandler {
+func newHTTPClient(cfg config.TimeoutConfig, tlsCfg *tls.Config) *http.Client {
 	transport := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout: time.Duration(cfg.ConnectMs) * time.Millisecond,
 		}).DialContext,
+		TLSClientConfig:     tlsCfg,
+		TLSHandshakeTimeout: time.Duration(cfg.ConnectMs) * time.Millisecond,
 		MaxIdleConnsPerHost: 100,
 		MaxIdleConns:        500,
 		IdleConnTimeout:     90 * time.Second,
 	}
-	client := &http.Client{
+	return &http.Client{
 		Transport: transport,
 		Timeout:   time.Duration(cfg.ResponseMs) * time.Millisecond,
 	}
-	return &ProxyHandler{
-		pool:       pool,
-		httpClient: client,
-		grpcProxy:  grpcProxy,
+}
+
+// clientFor 返回访问使用 tlsCfg 的实例的 HTTP 客户端
+func (p *ProxyHandler) clientFor(tlsCfg *tls.Config) *http.Client {
+	if tlsCfg == nil {
+		return p.httpClient
 	}
+	p.tlsMu.Lock()
+	defer p.tlsMu.Unlock()
+	client, ok := p.tlsClients[tlsCfg]
+	if !ok {
+		client = newHTTPClient(p.timeout, tlsCfg)
+		p.tlsClients[tlsCfg] = client
+	}
+	return client
 }
 
 // Handle 处理 /api/:service/*method 的泛化调用
 func (p *ProxyHandler) Handle(r *ghttp.Request) {
+	p.Forward(r, r.GetRouter("service").String(), r.GetRouter("method").String())
+}
+
+// Forward 将请求转发到 serviceName 的实例，method 为后端路径（HTTP）或 gRPC 方法
+func (p *ProxyHandler) Forward(r *ghttp.Request, serviceName, method string) {
 	ctx := r.GetCtx()
-	serviceName := r.GetRouter("service").String()
-	method := r.GetRouter("method").String()
 
 	// 校验服务名
 	if serviceName == "" {
@@ -74,47 +114,130 @@ func (p *ProxyHandler) Handle(r *ghttp.Request) {
 
 	// 按协议分发
 	if instance.Protocol == registry.ProtocolGRPC {
-		p.grpcProxy.Handle(r, instance.Address, method)
+		p.grpcProxy.Handle(r, instance.Address, p.tls.forInstance(serviceName, instance), method)
+		if r.Response.Status >= 500 {
+			p.outlier.RecordFailure(serviceName, instance.Address, len(resolver.GetInstances()))
+		} else {
+			p.outlier.RecordSuccess(serviceName, instance.Address)
+		}
 		return
 	}
 
-	// 2. 构建目标 URL（HTTP）
-	targetURL := fmt.Sprintf("http://%s/%s", instance.Address, method)
-	if r.URL.RawQuery != "" {
-		targetURL += "?" + r.URL.RawQuery
-	}
+	p.forwardHTTP(r, resolver, instance, serviceName, method)
+}
 
-	// 3. 创建转发请求
-	proxyReq, err := http.NewRequestWithContext(ctx, r.Method, targetURL, r.Body)
-	if err != nil {
-		g.Log().Errorf(ctx, "[gateway] create proxy request failed: %v", err)
-		GatewayError(r, CodeBackendError, "failed to create proxy request")
-		return
+// forwardHTTP 按服务重试策略转发 HTTP 请求，每次重试优先选择未尝试过的实例
+func (p *ProxyHandler) forwardHTTP(r *ghttp.Request, resolver *registry.Resolver, instance *registry.ServiceInstance, serviceName, method string) {
+	ctx := r.GetCtx()
+	policy := p.holder.Load().Retry.Policy(serviceName)
+
+	// 需要重试时缓存请求体，过大则退化为单次尝试
+	attempts := 1
+	var body []byte
+	if policy.Enabled && policy.MaxAttempts > 1 {
+		if buf, ok := bufferBody(r, policy.BufferLimitBytes); ok {
+			body = buf
+			attempts = policy.MaxAttempts
+		}
 	}
+	idempotent := policy.RetryNonIdempotent || isIdempotent(r.Method)
+	tried := make(map[string]bool, attempts)
 
-	// 4. 拷贝请求头（排除 hop-by-hop headers）
-	copyRequestHeaders(r.Request.Header, proxyReq.Header)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(retryBackoff(policy, attempt-1)):
+			case <-ctx.Done():
+				GatewayError(r, CodeBackendTimeout, fmt.Sprintf("backend timeout: %s", serviceName))
+				return
+			}
+			next, err := resolver.ResolveExcluding(tried)
+			if err != nil {
+				g.Log().Errorf(ctx, "[gateway] resolve for retry failed: %s: %v", serviceName, err)
+				GatewayError(r, CodeServiceNotFound, fmt.Sprintf("no available instance for %s", serviceName))
+				return
+			}
+			instance = next
+		}
+		tried[instance.ID] = true
+		last := attempt >= attempts
 
-	// 5. 执行转发
-	resp, err := p.httpClient.Do(proxyReq)
-	if err != nil {
-		g.Log().Errorf(ctx, "[gateway] proxy to %s failed: %v", targetURL, err)
-		if isTimeout(err) {
-			GatewayError(r, CodeBackendTimeout, fmt.Sprintf("backend timeout: %s", serviceName))
+		var reqBody io.Reader = r.Body
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+
+		// 1. 单次尝试超时
+		tryCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.Enabled && policy.PerTryTimeoutMs > 0 {
+			tryCtx, cancel = context.WithTimeout(ctx, time.Duration(policy.PerTryTimeoutMs)*time.Millisecond)
+		}
+
+		// 2. 构建目标 URL
+		tlsCfg := p.tls.forInstance(serviceName, instance)
+		scheme := "http"
+		if tlsCfg != nil {
+			scheme = "https"
+		}
+		targetURL := fmt.Sprintf("%s://%s/%s", scheme, instance.Address, method)
+		if r.URL.RawQuery != "" {
+			targetURL += "?" + r.URL.RawQuery
+		}
+
+		// 3. 创建转发请求
+		proxyReq, err := http.NewRequestWithContext(tryCtx, r.Method, targetURL, reqBody)
+		if err != nil {
+			cancel()
+			g.Log().Errorf(ctx, "[gateway] create proxy request failed: %v", err)
+			GatewayError(r, CodeBackendError, "failed to create proxy request")
+			return
+		}
+
+		// 4. 拷贝请求头（排除 hop-by-hop headers）
+		copyRequestHeaders(r.Request.Header, proxyReq.Header)
+
+		// 5. 执行转发
+		resp, err := p.clientFor(tlsCfg).Do(proxyReq)
+		if err != nil {
+			cancel()
+			p.outlier.RecordFailure(serviceName, instance.Address, len(resolver.GetInstances()))
+			g.Log().Errorf(ctx, "[gateway] proxy to %s failed (attempt %d/%d): %v", targetURL, attempt, attempts, err)
+			if !last && ctx.Err() == nil && retryableError(policy, err, idempotent) {
+				continue
+			}
+			if isTimeout(err) {
+				GatewayError(r, CodeBackendTimeout, fmt.Sprintf("backend timeout: %s", serviceName))
+			} else {
+				GatewayError(r, CodeBackendError, fmt.Sprintf("backend error: %s", serviceName))
+			}
+			return
+		}
+
+		if resp.StatusCode >= 500 {
+			p.outlier.RecordFailure(serviceName, instance.Address, len(resolver.GetInstances()))
 		} else {
-			GatewayError(r, CodeBackendError, fmt.Sprintf("backend error: %s", serviceName))
+			p.outlier.RecordSuccess(serviceName, instance.Address)
 		}
-		return
-	}
-	defer resp.Body.Close()
 
-	// 6. 拷贝响应头
-	copyResponseHeaders(resp.Header, r.Response.Header())
+		if !last && idempotent && retryableStatus(policy, resp.StatusCode) {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			cancel()
+			g.Log().Warningf(ctx, "[gateway] proxy to %s got %d (attempt %d/%d), retrying", targetURL, resp.StatusCode, attempt, attempts)
+			continue
+		}
+
+		// 6. 拷贝响应头
+		copyResponseHeaders(resp.Header, r.Response.Header())
 
-	// 7. 写入状态码和 body
-	r.Response.WriteStatus(resp.StatusCode)
-	if _, err := io.Copy(r.Response.RawWriter(), resp.Body); err != nil {
-		g.Log().Errorf(ctx, "[gateway] copy response body failed: %v", err)
+		// 7. 写入状态码和 body
+		r.Response.WriteStatus(resp.StatusCode)
+		if _, err := io.Copy(r.Response.RawWriter(), resp.Body); err != nil {
+			g.Log().Errorf(ctx, "[gateway] copy response body failed: %v", err)
+		}
+		resp.Body.Close()
+		cancel()
+		return
 	}
 }
 
@@ -15,13 +15,16 @@ type ResolverPool struct {
 	resolvers map[string]*registry.Resolver
 	reg       registry.Registry
 	picker    func() registry.Picker // 工厂函数，每个 Resolver 独立 Picker
+	opts      []registry.ResolverOption
 }
 
-func NewResolverPool(reg registry.Registry, pickerFactory func() registry.Picker) *ResolverPool {
+// NewResolverPool opts 会附加到每个新建的 Resolver（如健康检查）
+func NewResolverPool(reg registry.Registry, pickerFactory func() registry.Picker, opts ...registry.ResolverOption) *ResolverPool {
 	return &ResolverPool{
 		resolvers: make(map[string]*registry.Resolver),
 		reg:       reg,
 		picker:    pickerFactory,
+		opts:      opts,
 	}
 }
 
@@ -42,11 +45,8 @@ func (p *ResolverPool) GetOrCreate(serviceName string) (*registry.Resolver, erro
 		return r, nil
 	}
 
-	r, err := registry.NewResolver(
-		p.reg,
-		serviceName,
-		registry.WithPicker(p.picker()),
-	)
+	opts := append([]registry.ResolverOption{registry.WithPicker(p.picker())}, p.opts...)
+	r, err := registry.NewResolver(p.reg, serviceName, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("nexus-gateway: create resolver for %s: %w", serviceName, err)
 	}
@@ -18,6 +18,7 @@ const (
 	CodeServiceNotFound = internal.CodeServiceNotFound
 	CodeBackendTimeout  = internal.CodeBackendTimeout
 	CodeBackendError    = internal.CodeBackendError
+	CodeRouteNotFound   = internal.CodeRouteNotFound
 )
 
 func GetTraceID(ctx context.Context) string                     { return internal.GetTraceID(ctx) }
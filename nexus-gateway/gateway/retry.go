@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gogf/gf/v2/net/ghttp"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+)
+
+// 重试条件
+const (
+	retryOnConnectError = "connect-error" // 建立连接失败（请求未发出，非幂等请求也可安全重试）
+	retryOnTimeout      = "timeout"       // 单次尝试超时
+	retryOn5xx          = "5xx"           // 任意 5xx 响应或传输错误
+)
+
+// isIdempotent RFC 9110 定义的幂等方法
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func hasRetryOn(policy config.RetryPolicy, cond string) bool {
+	for _, c := range policy.RetryOn {
+		if c == cond {
+			return true
+		}
+	}
+	return false
+}
+
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryableError 判断传输错误是否可重试
+func retryableError(policy config.RetryPolicy, err error, idempotent bool) bool {
+	if isConnectError(err) {
+		return hasRetryOn(policy, retryOnConnectError) || hasRetryOn(policy, retryOn5xx)
+	}
+	if !idempotent {
+		return false
+	}
+	if isTimeout(err) {
+		return hasRetryOn(policy, retryOnTimeout)
+	}
+	return hasRetryOn(policy, retryOn5xx)
+}
+
+// retryableStatus 判断响应状态码是否可重试
+func retryableStatus(policy config.RetryPolicy, status int) bool {
+	for _, c := range policy.RetryOn {
+		if c == retryOn5xx && status >= 500 {
+			return true
+		}
+		if code, err := strconv.Atoi(c); err == nil && code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// retryBackoff 第 n 次重试前的等待时长（full jitter）
+func retryBackoff(policy config.RetryPolicy, n int) time.Duration {
+	base := time.Duration(policy.BackoffBaseMs) * time.Millisecond
+	limit := time.Duration(policy.BackoffMaxMs) * time.Millisecond
+	d := base << uint(n-1)
+	if d > limit || d <= 0 {
+		d = limit
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
+
+// bufferBody 缓存请求体以便重试时重放；超过 limit 时放弃缓存并还原 r.Body，返回 false
+func bufferBody(r *ghttp.Request, limit int64) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return []byte{}, true
+	}
+	if r.ContentLength > limit {
+		return nil, false
+	}
+	buf, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil || int64(len(buf)) > limit {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+		return nil, false
+	}
+	return buf, true
+}
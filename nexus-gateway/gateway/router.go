@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+)
+
+// compiledRoute 预编译后的路由规则
+type compiledRoute struct {
+	config.RouteConfig
+	regex   *regexp.Regexp
+	methods map[string]bool
+}
+
+// RouteMatch 路由匹配结果
+type RouteMatch struct {
+	Route   *config.RouteConfig
+	Service string
+	Path    string // 转发到后端的路径（不含前导斜杠）
+}
+
+// Router 动态路由表，规则变更时整体替换（无锁读取）
+type Router struct {
+	routes atomic.Pointer[[]*compiledRoute]
+}
+
+func NewRouter() *Router {
+	rt := &Router{}
+	rt.routes.Store(&[]*compiledRoute{})
+	return rt
+}
+
+// UpdateRoutes 编译并替换路由表，非法规则跳过并记录日志
+func (rt *Router) UpdateRoutes(routes []config.RouteConfig) {
+	compiled := make([]*compiledRoute, 0, len(routes))
+	for i := range routes {
+		rc := routes[i]
+		if rc.Service == "" {
+			log.Printf("[nexus-gateway] route %q skipped: empty service", rc.Name)
+			continue
+		}
+		cr := &compiledRoute{RouteConfig: rc}
+		if rc.PathRegex != "" {
+			re, err := regexp.Compile(rc.PathRegex)
+			if err != nil {
+				log.Printf("[nexus-gateway] route %q skipped: invalid path_regex: %v", rc.Name, err)
+				continue
+			}
+			cr.regex = re
+		}
+		if len(rc.Methods) > 0 {
+			cr.methods = make(map[string]bool, len(rc.Methods))
+			for _, m := range rc.Methods {
+				cr.methods[strings.ToUpper(m)] = true
+			}
+		}
+		compiled = append(compiled, cr)
+	}
+	rt.routes.Store(&compiled)
+	log.Printf("[nexus-gateway] routes updated: %d active", len(compiled))
+}
+
+// Match 按配置顺序匹配，返回第一条命中的路由
+func (rt *Router) Match(req *http.Request) (*RouteMatch, bool) {
+	path := req.URL.Path
+	for _, cr := range *rt.routes.Load() {
+		if !cr.matchHost(req.Host) || !cr.matchMethod(req.Method) || !cr.matchHeaders(req.Header) {
+			continue
+		}
+		target, ok := cr.rewritePath(path)
+		if !ok {
+			continue
+		}
+		return &RouteMatch{
+			Route:   &cr.RouteConfig,
+			Service: cr.Service,
+			Path:    strings.TrimPrefix(target, "/"),
+		}, true
+	}
+	return nil, false
+}
+
+func (cr *compiledRoute) matchHost(host string) bool {
+	if len(cr.Hosts) == 0 {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, pattern := range cr.Hosts {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func (cr *compiledRoute) matchMethod(method string) bool {
+	return cr.methods == nil || cr.methods[method]
+}
+
+func (cr *compiledRoute) matchHeaders(h http.Header) bool {
+	for k, v := range cr.Headers {
+		got := h.Values(k)
+		if len(got) == 0 {
+			return false
+		}
+		if v != "" && got[0] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// rewritePath 匹配路径并计算转发路径
+func (cr *compiledRoute) rewritePath(path string) (string, bool) {
+	if cr.regex != nil {
+		loc := cr.regex.FindStringSubmatchIndex(path)
+		if loc == nil {
+			return "", false
+		}
+		if cr.Rewrite == "" {
+			return path, true
+		}
+		// 只替换命中部分，保留其余路径
+		dst := cr.regex.ExpandString(nil, cr.Rewrite, path, loc)
+		return path[:loc[0]] + string(dst) + path[loc[1]:], true
+	}
+
+	if cr.PathPrefix != "" {
+		// 按路径段匹配：/api 匹配 /api、/api/x，不匹配 /apiv2/x
+		prefix := strings.TrimSuffix(cr.PathPrefix, "/")
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			return "", false
+		}
+		if cr.Rewrite != "" || cr.StripPrefix {
+			// rewrite 优先于 strip_prefix（strip 等价于 rewrite 为 "/"）
+			rest := strings.TrimPrefix(path[len(prefix):], "/")
+			return strings.TrimSuffix(cr.Rewrite, "/") + "/" + rest, true
+		}
+	}
+	return path, true
+}
+
+// ApplyHeaders 执行路由的请求头动作
+func (m *RouteMatch) ApplyHeaders(h http.Header) {
+	for _, k := range m.Route.RemoveHeaders {
+		h.Del(k)
+	}
+	for k, v := range m.Route.AddHeaders {
+		h.Set(k, v)
+	}
+}
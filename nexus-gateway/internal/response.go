@@ -17,6 +17,7 @@ const (
 	CodeServiceNotFound = 1005
 	CodeBackendTimeout  = 1006
 	CodeBackendError    = 1007
+	CodeRouteNotFound   = 1008
 )
 
 type Response struct {
@@ -76,6 +77,8 @@ func GatewayError(r *ghttp.Request, code int, msg string) {
 		WriteError(r, http.StatusGatewayTimeout, code, msg)
 	case CodeBackendError:
 		WriteError(r, http.StatusBadGateway, code, msg)
+	case CodeRouteNotFound:
+		WriteError(r, http.StatusNotFound, code, msg)
 	default:
 		WriteError(r, http.StatusInternalServerError, code, msg)
 	}
@@ -35,10 +35,18 @@ var (
 		},
 		[]string{"service"},
 	)
+
+	OutlierEjections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_outlier_ejections_total",
+			Help: "Total number of instance ejections by outlier detection per service",
+		},
+		[]string{"service"},
+	)
 )
 
 func init() {
-	prometheus.MustRegister(RequestTotal, RequestDuration, CircuitBreakerState)
+	prometheus.MustRegister(RequestTotal, RequestDuration, CircuitBreakerState, OutlierEjections)
 }
 
 // Register 绑定 Prometheus metrics 路由
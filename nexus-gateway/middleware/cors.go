@@ -25,22 +25,8 @@ func CORS(holder *config.DynamicConfigHolder) ghttp.HandlerFunc {
 			return
 		}
 
-		// 判断 origin 是否允许
-		allowAll := false
-		allowed := false
-		for _, o := range cfg.AllowOrigins {
-			if o == "*" {
-				allowAll = true
-				allowed = true
-				break
-			}
-			if o == origin {
-				allowed = true
-				break
-			}
-		}
-
-		if allowed {
+		allowAll := containsString(cfg.AllowOrigins, "*")
+		if OriginAllowed(cfg, origin) {
 			methods := strings.Join(cfg.AllowMethods, ", ")
 			headers := strings.Join(cfg.AllowHeaders, ", ")
 			maxAge := strconv.Itoa(cfg.MaxAgeSec)
@@ -70,6 +56,16 @@ func CORS(holder *config.DynamicConfigHolder) ghttp.HandlerFunc {
 	}
 }
 
+// OriginAllowed 判断 origin 是否在 allow_origins 中（* 表示全部）
+func OriginAllowed(cfg config.CORSConfig, origin string) bool {
+	for _, o := range cfg.AllowOrigins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
 func passthrough(r *ghttp.Request) {
 	r.Middleware.Next()
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net"
+	"sync"
 
 	"github.com/gogf/gf/v2/net/ghttp"
 
@@ -14,50 +15,64 @@ type ipEntry struct {
 	cidr *net.IPNet // CIDR 网段
 }
 
-// IPFilter IP 黑白名单过滤（动态读取配置）
-func IPFilter(holder *config.DynamicConfigHolder) ghttp.HandlerFunc {
+// IPMatcher IP 黑白名单判定（动态读取配置），HTTP 中间件与 gRPC 监听共用
+type IPMatcher struct {
+	holder *config.DynamicConfigHolder
+
 	// 缓存已解析的 IP 条目，避免每次请求重复解析
-	var (
-		cachedCfg     *config.IPFilterConfig
-		cachedEntries []ipEntry
-	)
+	mu            sync.Mutex
+	cachedCfg     *config.IPFilterConfig
+	cachedEntries []ipEntry
+}
 
-	return func(r *ghttp.Request) {
-		cfg := holder.Load().IPFilter
+func NewIPMatcher(holder *config.DynamicConfigHolder) *IPMatcher {
+	return &IPMatcher{holder: holder}
+}
 
-		if !cfg.Enabled {
-			r.Middleware.Next()
-			return
-		}
+// Check 返回该 IP 是否放行，拒绝时附带原因；无法解析的 IP 放行
+func (m *IPMatcher) Check(ip string) (bool, string) {
+	cfg := m.holder.Load().IPFilter
+	if !cfg.Enabled {
+		return true, ""
+	}
 
-		// 配置变更时重新解析
-		if cachedCfg == nil || !ipFilterEqual(cachedCfg, &cfg) {
-			cachedEntries = parseIPEntries(cfg.Addresses)
-			cfgCopy := cfg
-			cachedCfg = &cfgCopy
-		}
+	clientIP := net.ParseIP(ip)
+	if clientIP == nil {
+		return true, ""
+	}
 
-		clientIP := net.ParseIP(r.GetClientIp())
-		if clientIP == nil {
-			r.Middleware.Next()
-			return
-		}
+	m.mu.Lock()
+	// 配置变更时重新解析
+	if m.cachedCfg == nil || !ipFilterEqual(m.cachedCfg, &cfg) {
+		m.cachedEntries = parseIPEntries(cfg.Addresses)
+		cfgCopy := cfg
+		m.cachedCfg = &cfgCopy
+	}
+	entries := m.cachedEntries
+	m.mu.Unlock()
+
+	matched := matchIP(clientIP, entries)
 
-		matched := matchIP(clientIP, cachedEntries)
-
-		switch cfg.Mode {
-		case "whitelist":
-			if !matched {
-				internal.GatewayError(r, internal.CodeIPBlocked, "ip not allowed")
-				return
-			}
-		case "blacklist":
-			if matched {
-				internal.GatewayError(r, internal.CodeIPBlocked, "ip blocked")
-				return
-			}
+	switch cfg.Mode {
+	case "whitelist":
+		if !matched {
+			return false, "ip not allowed"
 		}
+	case "blacklist":
+		if matched {
+			return false, "ip blocked"
+		}
+	}
+	return true, ""
+}
 
+// IPFilter IP 黑白名单过滤（动态读取配置）
+func IPFilter(m *IPMatcher) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		if ok, reason := m.Check(r.GetClientIp()); !ok {
+			internal.GatewayError(r, internal.CodeIPBlocked, reason)
+			return
+		}
 		r.Middleware.Next()
 	}
 }
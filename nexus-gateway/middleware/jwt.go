@@ -9,6 +9,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/gogf/gf/v2/net/ghttp"
@@ -30,6 +31,11 @@ func GetUserClaims(ctx context.Context) jwt.MapClaims {
 	return nil
 }
 
+// WithUserClaims 将 JWT claims 存入 context
+func WithUserClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, userClaimsKey, claims)
+}
+
 // KeyManager JWKS 密钥管理器，支持多 kid 并发安全查找
 type KeyManager struct {
 	mu   sync.RWMutex
@@ -104,6 +110,22 @@ func (km *KeyManager) keyFunc(token *jwt.Token) (interface{}, error) {
 	return entry.pubKey, nil
 }
 
+// Verify 解析并校验 token（通过 kid 查找公钥），返回 claims
+func (km *KeyManager) Verify(tokenStr string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, km.keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %v", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	return claims, nil
+}
+
 // JWT 校验中间件，支持 JWKS 多密钥 + 动态配置热更新
 func JWT(holder *config.DynamicConfigHolder, km *KeyManager) ghttp.HandlerFunc {
 	return func(r *ghttp.Request) {
@@ -123,27 +145,21 @@ func JWT(holder *config.DynamicConfigHolder, km *KeyManager) ghttp.HandlerFunc {
 			}
 		}
 
-		// 提取 Bearer token
+		// 提取 Bearer token；浏览器 WebSocket 无法设置请求头，升级请求改从子协议或 query 中取
 		tokenStr := extractBearerToken(r)
+		if tokenStr == "" && isWebSocketUpgrade(r) {
+			if tokenStr = extractWebSocketToken(r); tokenStr != "" {
+				r.Request.Header.Set("Authorization", "Bearer "+tokenStr)
+			}
+		}
 		if tokenStr == "" {
 			internal.GatewayError(r, internal.CodeJWTInvalid, "missing authorization token")
 			return
 		}
 
-		// 解析并校验（通过 kid 查找公钥）
-		token, err := jwt.Parse(tokenStr, km.keyFunc)
-		if err != nil || !token.Valid {
-			msg := "invalid token"
-			if err != nil {
-				msg = fmt.Sprintf("invalid token: %v", err)
-			}
-			internal.GatewayError(r, internal.CodeJWTInvalid, msg)
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			internal.GatewayError(r, internal.CodeJWTInvalid, "invalid token claims")
+		claims, err := km.Verify(tokenStr)
+		if err != nil {
+			internal.GatewayError(r, internal.CodeJWTInvalid, err.Error())
 			return
 		}
 
@@ -156,8 +172,7 @@ func JWT(holder *config.DynamicConfigHolder, km *KeyManager) ghttp.HandlerFunc {
 		}
 
 		// 存入 context
-		ctx := context.WithValue(r.GetCtx(), userClaimsKey, claims)
-		r.SetCtx(ctx)
+		r.SetCtx(WithUserClaims(r.GetCtx(), claims))
 
 		r.Middleware.Next()
 	}
@@ -193,6 +208,40 @@ func parsePublicKey(pemStr string, algorithm string) (crypto.PublicKey, error) {
 	}
 }
 
+// WSTokenProtocol WebSocket 子协议携带令牌的约定：new WebSocket(url, ["bearer", token])，
+// 网关在握手响应中选择 bearer 子协议
+const WSTokenProtocol = "bearer"
+
+func isWebSocketUpgrade(r *ghttp.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, v := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractWebSocketToken 依次从 Sec-WebSocket-Protocol（bearer 之后的一项）和 access_token query 参数中取令牌
+func extractWebSocketToken(r *ghttp.Request) string {
+	var protocols []string
+	for _, v := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(v, ",") {
+			protocols = append(protocols, strings.TrimSpace(p))
+		}
+	}
+	for i := 0; i+1 < len(protocols); i++ {
+		if protocols[i] == WSTokenProtocol {
+			return protocols[i+1]
+		}
+	}
+	return r.URL.Query().Get("access_token")
+}
+
 func extractBearerToken(r *ghttp.Request) string {
 	auth := r.Header.Get("Authorization")
 	const prefix = "Bearer "
@@ -20,6 +20,12 @@ type tokenBucket struct {
 }
 
 func (tb *tokenBucket) allow(rate float64, capacity float64) bool {
+	ok, _, _ := tb.take(rate, capacity)
+	return ok
+}
+
+// take 尝试取一个令牌，返回剩余令牌数以及被拒绝时需等待的时长
+func (tb *tokenBucket) take(rate float64, capacity float64) (bool, float64, time.Duration) {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
 
@@ -40,13 +46,31 @@ func (tb *tokenBucket) allow(rate float64, capacity float64) bool {
 
 	if tb.tokens >= 1 {
 		tb.tokens--
-		return true
+		return true, tb.tokens, 0
 	}
-	return false
+	var wait time.Duration
+	if tb.rate > 0 {
+		wait = time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+	}
+	return false, tb.tokens, wait
+}
+
+// idleSince 返回最近一次取令牌的时间
+func (tb *tokenBucket) idleSince() time.Time {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.lastTime
+}
+
+// GlobalRateLimiter 全局限流器（动态读取配置），HTTP 中间件与 gRPC 监听共用同一份配额
+// store 非空且开启 distributed 时，多副本共享全局配额
+type GlobalRateLimiter struct {
+	holder *config.DynamicConfigHolder
+	window *slidingWindow
+	bucket *tokenBucket
 }
 
-// RateLimit 全局令牌桶限流中间件（动态读取配置）
-func RateLimit(holder *config.DynamicConfigHolder) ghttp.HandlerFunc {
+func NewGlobalRateLimiter(holder *config.DynamicConfigHolder, store RateLimitStore) *GlobalRateLimiter {
 	bucket := &tokenBucket{
 		tokens:   2000,
 		capacity: 2000,
@@ -61,15 +85,34 @@ func RateLimit(holder *config.DynamicConfigHolder) ghttp.HandlerFunc {
 		bucket.rate = cfg.RateLimit.Rate
 	}
 
-	return func(r *ghttp.Request) {
-		cfg := holder.Load().RateLimit
+	return &GlobalRateLimiter{
+		holder: holder,
+		window: newSlidingWindow(store),
+		bucket: bucket,
+	}
+}
 
-		if !cfg.Enabled {
-			r.Middleware.Next()
-			return
+// Allow 判断是否放行一个请求
+func (l *GlobalRateLimiter) Allow() bool {
+	cfg := l.holder.Load().RateLimit
+	if !cfg.Enabled {
+		return true
+	}
+
+	if cfg.Distributed && l.window.available() {
+		w := time.Duration(cfg.WindowSec) * time.Second
+		ok, _, _, err := l.window.take("global", cfg.Rate*float64(cfg.WindowSec), w)
+		if err == nil {
+			return ok
 		}
+	}
+	return l.bucket.allow(cfg.Rate, float64(cfg.Burst))
+}
 
-		if !bucket.allow(cfg.Rate, float64(cfg.Burst)) {
+// RateLimit 全局令牌桶限流中间件
+func RateLimit(limiter *GlobalRateLimiter) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		if !limiter.Allow() {
 			internal.GatewayError(r, internal.CodeRateLimited, "rate limit exceeded")
 			return
 		}
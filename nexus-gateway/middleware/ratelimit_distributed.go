@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimitStore 分布式限流的共享计数存储，网关多副本共用同一份计数
+// 内置 etcd 实现（EtcdRateLimitStore），也可接入 Redis 等其他存储
+type RateLimitStore interface {
+	// Add 将本节点在 key 上的计数原子累加 delta，返回全部节点在 key 上的计数之和，key 至少保留 ttl。
+	// 实现须使用原生的原子累加（如 Redis INCRBY）或每节点独立计数，不能依赖读-改-写重试
+	Add(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// Get 读取全部节点在 key 上的计数之和，不存在时返回 0
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+const (
+	storeTimeout    = 100 * time.Millisecond // 单次存储操作超时
+	storeCooldown   = 5 * time.Second        // 存储失败后改用本地令牌桶的时长
+	quotaBlockRatio = 0.05                   // 每次向共享存储申领的额度占窗口额度的比例
+)
+
+// slidingWindow 基于共享存储的滑动窗口计数器：
+// 估算值 = 上一窗口计数 × 上一窗口在滑动区间内的剩余占比 + 当前窗口计数。
+// 各节点按块向存储申领额度并在本地消耗，只有放行的请求占用额度，存储访问次数与请求量无关
+type slidingWindow struct {
+	store     RateLimitStore
+	downUntil atomic.Int64 // 存储不可用截止时间（unix nano）
+
+	mu        sync.Mutex
+	quotas    map[string]*windowQuota
+	lastSweep time.Time
+}
+
+// windowQuota 本节点在某个 key 当前窗口内的额度
+type windowQuota struct {
+	mu       sync.Mutex
+	slot     atomic.Int64 // 所属窗口编号
+	prev     int64        // 上一窗口全部节点的计数
+	global   int64        // 最近一次申领后当前窗口全部节点的计数
+	reserved int64        // 已申领、尚未使用的额度
+}
+
+func newSlidingWindow(store RateLimitStore) *slidingWindow {
+	if store == nil {
+		return nil
+	}
+	return &slidingWindow{
+		store:     store,
+		quotas:    make(map[string]*windowQuota),
+		lastSweep: time.Now(),
+	}
+}
+
+// available 存储可用时返回 true，失败冷却期内返回 false（调用方回退到本地限流）
+func (sw *slidingWindow) available() bool {
+	return sw != nil && time.Now().UnixNano() >= sw.downUntil.Load()
+}
+
+func (sw *slidingWindow) markDown(err error) {
+	sw.downUntil.Store(time.Now().Add(storeCooldown).UnixNano())
+	log.Printf("[nexus-gateway] rate limit store unavailable, fallback to local for %v: %v", storeCooldown, err)
+}
+
+// quota 返回 key 的本地额度，顺带回收两个窗口之前的记录（最多每个窗口执行一次）
+func (sw *slidingWindow) quota(key string, slot int64, window time.Duration) *windowQuota {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if now := time.Now(); now.Sub(sw.lastSweep) > window {
+		sw.lastSweep = now
+		for k, q := range sw.quotas {
+			if q.slot.Load() < slot-1 {
+				delete(sw.quotas, k)
+			}
+		}
+	}
+
+	q, ok := sw.quotas[key]
+	if !ok {
+		q = &windowQuota{}
+		q.slot.Store(-1)
+		sw.quotas[key] = q
+	}
+	return q
+}
+
+// take 在 window 内最多放行 limit 个请求，返回是否放行、剩余额度和建议等待时长
+func (sw *slidingWindow) take(key string, limit float64, window time.Duration) (bool, float64, time.Duration, error) {
+	now := time.Now().UnixNano()
+	slot := now / int64(window)
+	elapsed := time.Duration(now - slot*int64(window))
+	weight := 1 - float64(elapsed)/float64(window)
+
+	q := sw.quota(key, slot, window)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	// 进入新窗口：归还上一窗口未用完的额度，再读取一次上一窗口的最终计数
+	if q.slot.Load() != slot {
+		if q.slot.Load() == slot-1 && q.reserved > 0 {
+			if _, err := sw.store.Add(ctx, fmt.Sprintf("%s/%d", key, slot-1), -q.reserved, 2*window); err != nil {
+				sw.markDown(err)
+				return false, 0, 0, err
+			}
+		}
+		prev, err := sw.store.Get(ctx, fmt.Sprintf("%s/%d", key, slot-1))
+		if err != nil {
+			sw.markDown(err)
+			return false, 0, 0, err
+		}
+		q.prev, q.global, q.reserved = prev, 0, 0
+		q.slot.Store(slot)
+	}
+
+	base := float64(q.prev) * weight
+	if q.reserved > 0 {
+		q.reserved--
+		return true, limit - base - float64(q.global) + float64(q.reserved), 0, nil
+	}
+
+	// 按已知的全局计数已经超限：直接拒绝，不占用共享额度
+	free := limit - base - float64(q.global)
+	if free < 1 {
+		return false, 0, window - elapsed, nil
+	}
+
+	block := int64(math.Max(1, math.Min(math.Ceil(limit*quotaBlockRatio), math.Floor(free))))
+	total, err := sw.store.Add(ctx, fmt.Sprintf("%s/%d", key, slot), block, 2*window)
+	if err != nil {
+		sw.markDown(err)
+		return false, 0, 0, err
+	}
+	q.global = total
+
+	// 申领到的区间为 (total-block, total]，其中不超过 limit 的部分可用
+	usable := int64(math.Floor(limit-base)) - (total - block)
+	if usable > block {
+		usable = block
+	}
+	if usable < block {
+		// 超出 limit 的部分归还，被拒绝的请求不占用额度
+		unused := block - max(usable, 0)
+		if total, err = sw.store.Add(ctx, fmt.Sprintf("%s/%d", key, slot), -unused, 2*window); err != nil {
+			sw.markDown(err)
+			return false, 0, 0, err
+		}
+		q.global = total
+	}
+	if usable <= 0 {
+		return false, 0, window - elapsed, nil
+	}
+	q.reserved = usable - 1
+	return true, limit - base - float64(q.global) + float64(q.reserved), 0, nil
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdRateLimitStore 基于 etcd 的 RateLimitStore：
+// 每个网关节点只写自己名下的计数（<prefix><key>/<node>），节点之间没有写冲突；
+// 累加与汇总全部节点计数在同一个事务中完成。同一 ttl 的 key 共享租约自动过期
+type EtcdRateLimitStore struct {
+	client *clientv3.Client
+	prefix string
+	node   string // 本节点标识，进程内唯一
+
+	mu        sync.Mutex
+	leases    map[time.Duration]*rateLease
+	counts    map[string]*nodeCount // key → 本节点计数
+	lastSweep time.Time
+}
+
+type rateLease struct {
+	id        clientv3.LeaseID
+	grantedAt time.Time
+}
+
+// nodeCount 本节点在某个 key 上的累计值，mu 保证同一 key 的写入按累加顺序到达 etcd
+type nodeCount struct {
+	mu      sync.Mutex
+	val     int64
+	expires time.Time
+}
+
+// 编译期检查：确保实现了 RateLimitStore 接口
+var _ RateLimitStore = (*EtcdRateLimitStore)(nil)
+
+func NewEtcdRateLimitStore(client *clientv3.Client, prefix string) *EtcdRateLimitStore {
+	if prefix == "" {
+		prefix = "/nexus/gateway/ratelimit/"
+	}
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return &EtcdRateLimitStore{
+		client:    client,
+		prefix:    prefix,
+		node:      hex.EncodeToString(buf),
+		leases:    make(map[time.Duration]*rateLease),
+		counts:    make(map[string]*nodeCount),
+		lastSweep: time.Now(),
+	}
+}
+
+// lease 返回 ttl 对应的共享租约：每 ttl 轮换一次、有效期 2×ttl，保证挂在其上的 key 至少保留 ttl
+func (s *EtcdRateLimitStore) lease(ctx context.Context, ttl time.Duration) (clientv3.LeaseID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.leases[ttl]; ok && time.Since(l.grantedAt) < ttl {
+		return l.id, nil
+	}
+	resp, err := s.client.Grant(ctx, int64(math.Ceil((2 * ttl).Seconds())))
+	if err != nil {
+		return 0, fmt.Errorf("nexus-gateway: grant rate limit lease: %w", err)
+	}
+	s.leases[ttl] = &rateLease{id: resp.ID, grantedAt: time.Now()}
+	return resp.ID, nil
+}
+
+// count 返回本节点在 key 上的计数，顺带回收已过期的记录
+func (s *EtcdRateLimitStore) count(key string, ttl time.Duration) *nodeCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > ttl {
+		s.lastSweep = now
+		for k, c := range s.counts {
+			if now.After(c.expires) {
+				delete(s.counts, k)
+			}
+		}
+	}
+
+	c, ok := s.counts[key]
+	if !ok || now.After(c.expires) {
+		c = &nodeCount{expires: now.Add(ttl)}
+		s.counts[key] = c
+	}
+	return c
+}
+
+func (s *EtcdRateLimitStore) Add(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	leaseID, err := s.lease(ctx, ttl)
+	if err != nil {
+		return 0, err
+	}
+
+	c := s.count(key, ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := s.prefix + key + "/"
+	resp, err := s.client.Txn(ctx).
+		Then(
+			clientv3.OpPut(prefix+s.node, strconv.FormatInt(c.val+delta, 10), clientv3.WithLease(leaseID)),
+			clientv3.OpGet(prefix, clientv3.WithPrefix()),
+		).
+		Commit()
+	if err != nil {
+		return 0, fmt.Errorf("nexus-gateway: add %s: %w", prefix+s.node, err)
+	}
+	c.val += delta
+
+	return sumCounts(resp.Responses[1].GetResponseRange().Kvs), nil
+}
+
+func (s *EtcdRateLimitStore) Get(ctx context.Context, key string) (int64, error) {
+	prefix := s.prefix + key + "/"
+	resp, err := s.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("nexus-gateway: get %s: %w", prefix, err)
+	}
+	return sumCounts(resp.Kvs), nil
+}
+
+func sumCounts(kvs []*mvccpb.KeyValue) int64 {
+	var total int64
+	for _, kv := range kvs {
+		n, _ := strconv.ParseInt(string(kv.Value), 10, 64)
+		total += n
+	}
+	return total
+}
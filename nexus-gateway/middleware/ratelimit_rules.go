@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/krustd/gf-nexus/nexus-gateway/config"
+	"github.com/krustd/gf-nexus/nexus-gateway/internal"
+)
+
+// 限流 key 类型
+const (
+	rateKeyService = "service"
+	rateKeyRoute   = "route"
+	rateKeyIP      = "ip"
+	rateKeyHeader  = "header:"
+	rateKeyClaim   = "claim:"
+)
+
+// RateLimitManager 按规则分桶限流（动态读取配置）
+// 在路由确定之后、JWT 校验之后调用，因此可以按服务、路由和 JWT claim 取 key
+type RateLimitManager struct {
+	holder *config.DynamicConfigHolder
+	window *slidingWindow // 分布式模式，未配置存储时为 nil
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket // 规则名 + key → 令牌桶
+	lastSweep time.Time
+}
+
+// NewRateLimitManager store 为空时仅支持本地限流
+func NewRateLimitManager(holder *config.DynamicConfigHolder, store RateLimitStore) *RateLimitManager {
+	return &RateLimitManager{
+		holder:    holder,
+		window:    newSlidingWindow(store),
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// RateLimitSubject 限流 key 的取值来源，与传输协议无关
+type RateLimitSubject struct {
+	ClientIP string
+	Header   func(name string) string // 请求头 / gRPC metadata
+	Claims   jwt.MapClaims            // JWT 未启用或未携带时为 nil
+}
+
+// RateLimitResult 规则检查结果
+type RateLimitResult struct {
+	Allowed    bool
+	Matched    bool          // 至少命中一条规则
+	Rule       string        // 拒绝时为触发的规则名
+	Limit      int           // 剩余最少的规则的额度
+	Remaining  int           // 剩余最少的规则的剩余额度
+	RetryAfter time.Duration // 拒绝时的建议等待时长
+}
+
+// Allow 依次检查命中的规则，全部放行返回 true；
+// 写入 X-RateLimit-Limit / X-RateLimit-Remaining（取剩余最少的规则），拒绝时写入 Retry-After 和错误响应
+func (m *RateLimitManager) Allow(r *ghttp.Request, serviceName, routeName string) bool {
+	res := m.Check(RateLimitSubject{
+		ClientIP: r.GetClientIp(),
+		Header:   r.Request.Header.Get,
+		Claims:   GetUserClaims(r.GetCtx()),
+	}, serviceName, routeName)
+
+	if !res.Allowed {
+		h := r.Response.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("X-RateLimit-Remaining", "0")
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+		internal.GatewayError(r, internal.CodeRateLimited, fmt.Sprintf("rate limit exceeded: %s", res.Rule))
+		return false
+	}
+	if res.Matched {
+		h := r.Response.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	}
+	return true
+}
+
+// Check 依次检查命中的规则，遇到第一条拒绝的规则即返回
+func (m *RateLimitManager) Check(subject RateLimitSubject, serviceName, routeName string) RateLimitResult {
+	cfg := m.holder.Load().RateLimit
+	if !cfg.Enabled || len(cfg.Rules) == 0 {
+		return RateLimitResult{Allowed: true}
+	}
+	m.sweep(time.Duration(cfg.IdleTimeoutSec) * time.Second)
+
+	res := RateLimitResult{Allowed: true}
+	remaining := math.MaxFloat64
+	for i, rule := range cfg.Rules {
+		if rule.Rate <= 0 || !matchRule(rule, serviceName, routeName) {
+			continue
+		}
+		key, ok := extractRateKey(subject, rule.Key, serviceName, routeName)
+		if !ok {
+			continue
+		}
+
+		name := rule.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		ok, quota, left, wait := m.take(cfg, name+"|"+key, rule)
+		if !ok {
+			return RateLimitResult{Matched: true, Rule: name, Limit: quota, RetryAfter: wait}
+		}
+		if !res.Matched || left < remaining {
+			res.Limit, remaining = quota, left
+		}
+		res.Matched = true
+	}
+	if res.Matched {
+		res.Remaining = int(remaining)
+	}
+	return res
+}
+
+// take 分布式模式下使用共享滑动窗口（额度为 rate × window_sec），否则或存储不可用时使用本地令牌桶（额度为 burst）
+func (m *RateLimitManager) take(cfg config.RateLimitConfig, key string, rule config.RateLimitRule) (bool, int, float64, time.Duration) {
+	if cfg.Distributed && m.window.available() {
+		limit := rule.Rate * float64(cfg.WindowSec)
+		ok, left, wait, err := m.window.take(key, limit, time.Duration(cfg.WindowSec)*time.Second)
+		if err == nil {
+			return ok, int(limit), left, wait
+		}
+	}
+	ok, left, wait := m.bucket(key, rule).take(rule.Rate, float64(rule.Burst))
+	return ok, rule.Burst, left, wait
+}
+
+func (m *RateLimitManager) bucket(key string, rule config.RateLimitRule) *tokenBucket {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tb, ok := m.buckets[key]
+	if !ok {
+		tb = &tokenBucket{
+			tokens:   float64(rule.Burst),
+			capacity: float64(rule.Burst),
+			rate:     rule.Rate,
+			lastTime: time.Now(),
+		}
+		m.buckets[key] = tb
+	}
+	return tb
+}
+
+// sweep 回收空闲令牌桶（最多每个 idle 周期执行一次）
+func (m *RateLimitManager) sweep(idle time.Duration) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.lastSweep) < idle {
+		return
+	}
+	m.lastSweep = now
+	for key, tb := range m.buckets {
+		if now.Sub(tb.idleSince()) > idle {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func matchRule(rule config.RateLimitRule, serviceName, routeName string) bool {
+	if len(rule.Services) > 0 && !containsString(rule.Services, serviceName) {
+		return false
+	}
+	if len(rule.Routes) > 0 && !containsString(rule.Routes, routeName) {
+		return false
+	}
+	return true
+}
+
+// extractRateKey 按规则取限流 key，取不到（如缺少请求头或 claim）时规则不生效
+func extractRateKey(subject RateLimitSubject, keyType, serviceName, routeName string) (string, bool) {
+	switch {
+	case keyType == rateKeyService:
+		return serviceName, true
+	case keyType == rateKeyRoute:
+		return routeName, routeName != ""
+	case keyType == rateKeyIP:
+		return subject.ClientIP, subject.ClientIP != ""
+	case strings.HasPrefix(keyType, rateKeyHeader):
+		if subject.Header == nil {
+			return "", false
+		}
+		v := subject.Header(strings.TrimPrefix(keyType, rateKeyHeader))
+		return v, v != ""
+	case strings.HasPrefix(keyType, rateKeyClaim):
+		if subject.Claims == nil {
+			return "", false
+		}
+		v, ok := subject.Claims[strings.TrimPrefix(keyType, rateKeyClaim)]
+		if !ok || v == nil {
+			return "", false
+		}
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
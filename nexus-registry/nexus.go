@@ -11,7 +11,12 @@ import (
 	"time"
 
 	"github.com/krustd/gf-nexus/nexus-registry/registry"
-	"github.com/krustd/gf-nexus/nexus-registry/registry/etcd"
+
+	// 内置驱动，通过 [nexus.registry] driver 选择
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/consul"
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/etcd"
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/file"
+	_ "github.com/krustd/gf-nexus/nexus-registry/registry/memory"
 )
 
 var currentInstance *registry.ServiceInstance
@@ -23,8 +28,8 @@ func Setup(configPath string) error {
 		return fmt.Errorf("nexus: load config: %w", err)
 	}
 
-	// ★ 唯一绑定具体实现的地方，将来换 consul 只改这一行
-	reg, err := etcd.New(&conf.Registry)
+	// 按配置中的 driver 选择具体实现
+	reg, err := registry.New(&conf.Registry)
 	if err != nil {
 		return fmt.Errorf("nexus: create registry: %w", err)
 	}
@@ -57,7 +62,7 @@ func SetupMulti(configPath string, instances ...*registry.ServiceInstance) error
 		return fmt.Errorf("nexus: load config: %w", err)
 	}
 
-	reg, err := etcd.New(&conf.Registry)
+	reg, err := registry.New(&conf.Registry)
 	if err != nil {
 		return fmt.Errorf("nexus: create registry: %w", err)
 	}
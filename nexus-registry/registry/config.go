@@ -8,13 +8,24 @@ import (
 	"github.com/BurntSushi/toml"
 )
 
+// 内置驱动名
+const (
+	DriverEtcd   = "etcd"
+	DriverMemory = "memory"
+	DriverConsul = "consul"
+	DriverFile   = "file"
+)
+
 type Config struct {
+	Driver         string   `toml:"driver"         json:"driver"` // etcd / memory / consul / file
 	Endpoints      []string `toml:"endpoints"      json:"endpoints"`
 	DialTimeoutSec int      `toml:"dial_timeout"   json:"dial_timeout"`
 	LeaseTTL       int64    `toml:"lease_ttl"      json:"lease_ttl"`
 	Prefix         string   `toml:"prefix"         json:"prefix"`
 	Username       string   `toml:"username,omitempty" json:"username,omitempty"`
 	Password       string   `toml:"password,omitempty" json:"password,omitempty"`
+	Token          string   `toml:"token,omitempty"    json:"token,omitempty"` // consul ACL token
+	Path           string   `toml:"path,omitempty"     json:"path,omitempty"`  // file 驱动的数据目录
 }
 
 type ServiceConfig struct {
@@ -57,6 +68,7 @@ func (c *Config) Validate() error {
 
 func DefaultConfig() *Config {
 	return &Config{
+		Driver:         DriverEtcd,
 		Endpoints:      []string{"127.0.0.1:2379"},
 		DialTimeoutSec: 5,
 		LeaseTTL:       15,
@@ -74,6 +86,9 @@ func LoadConfig(path string) (*NexusConfig, error) {
 		return nil, fmt.Errorf("nexus: parse toml %s: %w", path, err)
 	}
 	conf := &root.Nexus
+	if conf.Registry.Driver == "" {
+		conf.Registry.Driver = DriverEtcd
+	}
 	if len(conf.Registry.Endpoints) == 0 {
 		conf.Registry.Endpoints = []string{"127.0.0.1:2379"}
 	}
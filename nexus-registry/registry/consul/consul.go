@@ -0,0 +1,500 @@
+// Package consul 基于 Consul HTTP API 的 Registry 实现。
+//
+// 直接调用 Agent / Health 接口，不依赖 consul 官方 SDK：
+//   - Register   → PUT /v1/agent/service/register（附带 TTL 健康检查）
+//   - 续约        → PUT /v1/agent/check/pass/service:{id}
+//   - Discover   → GET /v1/health/service/{name}?passing=true
+//   - Watch      → 同上，使用 index + wait 阻塞查询
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
+)
+
+// Meta 中的保留键，其余键原样映射到 ServiceInstance.Metadata
+const (
+	metaInstanceID = "nexus_id"
+	metaVersion    = "nexus_version"
+	metaProtocol   = "nexus_protocol"
+	metaWeight     = "nexus_weight"
+	metaAddress    = "nexus_address"
+)
+
+const (
+	watchWait        = 5 * time.Minute
+	retryBackoff     = time.Second
+	watchMinInterval = time.Second // index 未变化的阻塞查询之间的最小间隔
+)
+
+// ConsulRegistry consul 实现
+type ConsulRegistry struct {
+	addr   string
+	client *http.Client // 普通请求
+	watch  *http.Client // 阻塞查询专用，超时需大于 wait
+	config *registry.Config
+
+	mu         sync.Mutex
+	registered map[string]*registry.ServiceInstance // consul service id → instance
+	cancels    map[string]context.CancelFunc        // keepalive cancel per id
+}
+
+// 编译期检查：确保实现了 Registry 接口
+var _ registry.Registry = (*ConsulRegistry)(nil)
+
+func init() {
+	registry.RegisterDriver(registry.DriverConsul, func(conf *registry.Config) (registry.Registry, error) {
+		return New(conf)
+	})
+}
+
+func New(conf *registry.Config) (*ConsulRegistry, error) {
+	if conf == nil {
+		conf = registry.DefaultConfig()
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	addr := conf.Endpoints[0]
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+
+	r := &ConsulRegistry{
+		addr:       strings.TrimSuffix(addr, "/"),
+		client:     &http.Client{Timeout: conf.DialTimeout()},
+		watch:      &http.Client{Timeout: watchWait + 30*time.Second},
+		config:     conf,
+		registered: make(map[string]*registry.ServiceInstance),
+		cancels:    make(map[string]context.CancelFunc),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.DialTimeout())
+	defer cancel()
+	if err := r.do(ctx, r.client, http.MethodGet, "/v1/status/leader", nil, nil); err != nil {
+		return nil, fmt.Errorf("nexus-consul: health check failed: %w", err)
+	}
+	return r, nil
+}
+
+// ==================== Consul API 结构 ====================
+
+type agentServiceRegistration struct {
+	ID      string            `json:"ID"`
+	Name    string            `json:"Name"`
+	Tags    []string          `json:"Tags,omitempty"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Meta    map[string]string `json:"Meta,omitempty"`
+	Weights *agentWeights     `json:"Weights,omitempty"`
+	Check   *agentCheck       `json:"Check,omitempty"`
+}
+
+type agentWeights struct {
+	Passing int `json:"Passing"`
+	Warning int `json:"Warning"`
+}
+
+type agentCheck struct {
+	CheckID                        string `json:"CheckID"`
+	TTL                            string `json:"TTL"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter,omitempty"`
+}
+
+type healthServiceEntry struct {
+	Service struct {
+		ID      string            `json:"ID"`
+		Service string            `json:"Service"`
+		Tags    []string          `json:"Tags"`
+		Address string            `json:"Address"`
+		Port    int               `json:"Port"`
+		Meta    map[string]string `json:"Meta"`
+		Weights agentWeights      `json:"Weights"`
+	} `json:"Service"`
+}
+
+// ==================== 映射 ====================
+
+func checkID(serviceID string) string {
+	return "service:" + serviceID
+}
+
+func toRegistration(instance *registry.ServiceInstance, ttl time.Duration) (*agentServiceRegistration, error) {
+	host, portStr, err := net.SplitHostPort(instance.Address)
+	if err != nil {
+		return nil, fmt.Errorf("nexus-consul: invalid address %s: %w", instance.Address, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("nexus-consul: invalid port %s: %w", portStr, err)
+	}
+
+	meta := make(map[string]string, len(instance.Metadata)+5)
+	for k, v := range instance.Metadata {
+		meta[k] = v
+	}
+	meta[metaInstanceID] = instance.ID
+	meta[metaVersion] = instance.Version
+	meta[metaProtocol] = string(instance.Protocol)
+	meta[metaWeight] = strconv.Itoa(instance.Weight)
+	meta[metaAddress] = instance.Address
+
+	tags := []string{"protocol=" + string(instance.Protocol)}
+	if instance.Version != "" {
+		tags = append(tags, "version="+instance.Version)
+	}
+
+	return &agentServiceRegistration{
+		ID:      instance.ID,
+		Name:    instance.Name,
+		Tags:    tags,
+		Address: host,
+		Port:    port,
+		Meta:    meta,
+		Weights: &agentWeights{Passing: instance.Weight, Warning: 1},
+		Check: &agentCheck{
+			CheckID:                        checkID(instance.ID),
+			TTL:                            ttl.String(),
+			DeregisterCriticalServiceAfter: (ttl * 4).String(),
+		},
+	}, nil
+}
+
+func fromHealthEntry(e *healthServiceEntry) *registry.ServiceInstance {
+	svc := e.Service
+	inst := &registry.ServiceInstance{
+		ID:       svc.ID,
+		Name:     svc.Service,
+		Address:  net.JoinHostPort(svc.Address, strconv.Itoa(svc.Port)),
+		Weight:   svc.Weights.Passing,
+		Protocol: registry.ProtocolHTTP,
+	}
+
+	for k, v := range svc.Meta {
+		switch k {
+		case metaInstanceID:
+			if v != "" {
+				inst.ID = v
+			}
+		case metaVersion:
+			inst.Version = v
+		case metaProtocol:
+			if v != "" {
+				inst.Protocol = registry.Protocol(v)
+			}
+		case metaWeight:
+			if w, err := strconv.Atoi(v); err == nil && w > 0 {
+				inst.Weight = w
+			}
+		case metaAddress:
+			if v != "" {
+				inst.Address = v
+			}
+		default:
+			if inst.Metadata == nil {
+				inst.Metadata = make(map[string]string)
+			}
+			inst.Metadata[k] = v
+		}
+	}
+
+	// 非本 SDK 注册的服务：从 tags 兜底解析协议和版本
+	if _, ok := svc.Meta[metaProtocol]; !ok {
+		for _, tag := range svc.Tags {
+			if v, ok := strings.CutPrefix(tag, "protocol="); ok {
+				inst.Protocol = registry.Protocol(v)
+			} else if v, ok := strings.CutPrefix(tag, "version="); ok {
+				inst.Version = v
+			}
+		}
+	}
+	if inst.Weight <= 0 {
+		inst.Weight = 1
+	}
+	return inst
+}
+
+// ==================== Registry 接口 ====================
+
+func (r *ConsulRegistry) Register(ctx context.Context, instance *registry.ServiceInstance) error {
+	if err := instance.Validate(); err != nil {
+		return err
+	}
+
+	ttl := time.Duration(r.config.LeaseTTL) * time.Second
+	reg, err := toRegistration(instance, ttl)
+	if err != nil {
+		return err
+	}
+
+	if err := r.do(ctx, r.client, http.MethodPut, "/v1/agent/service/register", reg, nil); err != nil {
+		return fmt.Errorf("nexus-consul: register %s: %w", reg.ID, err)
+	}
+	// 注册后立即置为 passing，否则需等待首个 TTL 周期才可被发现
+	if err := r.pass(ctx, reg.ID); err != nil {
+		return fmt.Errorf("nexus-consul: pass check %s: %w", reg.ID, err)
+	}
+
+	// KeepAlive 使用独立的 background context，不受调用方 ctx 生命周期影响
+	kaCtx, kaCancel := context.WithCancel(context.Background())
+	go r.keepAlive(kaCtx, reg.ID, ttl)
+
+	r.mu.Lock()
+	if cancel, ok := r.cancels[reg.ID]; ok {
+		cancel()
+	}
+	r.registered[reg.ID] = instance
+	r.cancels[reg.ID] = kaCancel
+	r.mu.Unlock()
+
+	log.Printf("[nexus-consul] registered: %s → %s (%s)", reg.ID, instance.Address, instance.Protocol)
+	return nil
+}
+
+func (r *ConsulRegistry) keepAlive(ctx context.Context, serviceID string, ttl time.Duration) {
+	interval := ttl / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[nexus-consul] keepalive closed: %s", serviceID)
+			return
+		case <-ticker.C:
+			if err := r.pass(ctx, serviceID); err != nil && ctx.Err() == nil {
+				log.Printf("[nexus-consul] keepalive %s failed: %v", serviceID, err)
+			}
+		}
+	}
+}
+
+func (r *ConsulRegistry) pass(ctx context.Context, serviceID string) error {
+	return r.do(ctx, r.client, http.MethodPut, "/v1/agent/check/pass/"+url.PathEscape(checkID(serviceID)), nil, nil)
+}
+
+func (r *ConsulRegistry) Deregister(ctx context.Context, instance *registry.ServiceInstance) error {
+	id := instance.ID
+	if id == "" {
+		id = instance.Address
+	}
+
+	r.mu.Lock()
+	delete(r.registered, id)
+	if cancel, ok := r.cancels[id]; ok {
+		cancel()
+		delete(r.cancels, id)
+	}
+	r.mu.Unlock()
+
+	if err := r.do(ctx, r.client, http.MethodPut, "/v1/agent/service/deregister/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("nexus-consul: deregister %s: %w", id, err)
+	}
+
+	log.Printf("[nexus-consul] deregistered: %s", id)
+	return nil
+}
+
+func (r *ConsulRegistry) Discover(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	instances, _, err := r.query(ctx, r.client, serviceName, 0)
+	if err != nil {
+		return nil, fmt.Errorf("nexus-consul: discover %s: %w", serviceName, err)
+	}
+	return instances, nil
+}
+
+func (r *ConsulRegistry) DiscoverByProtocol(ctx context.Context, serviceName string, protocol registry.Protocol) ([]*registry.ServiceInstance, error) {
+	all, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*registry.ServiceInstance, 0)
+	for _, inst := range all {
+		if inst.Protocol == protocol {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered, nil
+}
+
+// Watch 基于阻塞查询轮询健康实例列表，与上一次结果做差集生成 Put / Delete 事件
+func (r *ConsulRegistry) Watch(ctx context.Context, serviceName string) (<-chan registry.WatchEvent, error) {
+	current, index, err := r.query(ctx, r.client, serviceName, 0)
+	if err != nil {
+		return nil, fmt.Errorf("nexus-consul: watch %s: %w", serviceName, err)
+	}
+	if index < 1 {
+		index = 1
+	}
+
+	known := make(map[string]string, len(current)) // id → 序列化结果，用于判断变更
+	registry.DiffInstances(known, current)
+
+	eventCh := make(chan registry.WatchEvent, 64)
+	go func() {
+		defer close(eventCh)
+		for {
+			start := time.Now()
+			instances, newIndex, err := r.query(ctx, r.watch, serviceName, index)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("[nexus-consul] watch %s failed: %v", serviceName, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retryBackoff):
+				}
+				continue
+			}
+
+			// 见 consul 阻塞查询文档：index 回退时重置；index 为 0 时不会阻塞，至少取 1
+			if newIndex < index || newIndex < 1 {
+				newIndex = 1
+			}
+			unchanged := newIndex == index
+			index = newIndex
+
+			events := registry.DiffInstances(known, instances)
+			for _, ev := range events {
+				select {
+				case eventCh <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// index 未变化却立即返回时限速，避免对 agent 空转
+			if wait := watchMinInterval - time.Since(start); unchanged && wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+func (r *ConsulRegistry) Close(ctx context.Context) error {
+	r.mu.Lock()
+	for _, cancel := range r.cancels {
+		cancel()
+	}
+	ids := make([]string, 0, len(r.registered))
+	for id := range r.registered {
+		ids = append(ids, id)
+	}
+	r.registered = make(map[string]*registry.ServiceInstance)
+	r.cancels = make(map[string]context.CancelFunc)
+	r.mu.Unlock()
+
+	for _, id := range ids {
+		if err := r.do(ctx, r.client, http.MethodPut, "/v1/agent/service/deregister/"+url.PathEscape(id), nil, nil); err != nil {
+			log.Printf("[nexus-consul] deregister %s failed: %v", id, err)
+		}
+	}
+
+	r.client.CloseIdleConnections()
+	r.watch.CloseIdleConnections()
+	return nil
+}
+
+// ==================== HTTP ====================
+
+// query 查询健康实例；index > 0 时为阻塞查询
+func (r *ConsulRegistry) query(ctx context.Context, client *http.Client, serviceName string, index uint64) ([]*registry.ServiceInstance, uint64, error) {
+	params := url.Values{}
+	params.Set("passing", "true")
+	if index > 0 {
+		params.Set("index", strconv.FormatUint(index, 10))
+		params.Set("wait", fmt.Sprintf("%ds", int(watchWait.Seconds())))
+	}
+
+	var entries []*healthServiceEntry
+	header := http.Header{}
+	path := "/v1/health/service/" + url.PathEscape(serviceName) + "?" + params.Encode()
+	if err := r.doWithHeader(ctx, client, http.MethodGet, path, nil, &entries, header); err != nil {
+		return nil, 0, err
+	}
+
+	newIndex, _ := strconv.ParseUint(header.Get("X-Consul-Index"), 10, 64)
+
+	instances := make([]*registry.ServiceInstance, 0, len(entries))
+	for _, e := range entries {
+		instances = append(instances, fromHealthEntry(e))
+	}
+	return instances, newIndex, nil
+}
+
+func (r *ConsulRegistry) do(ctx context.Context, client *http.Client, method, path string, in, out interface{}) error {
+	return r.doWithHeader(ctx, client, method, path, in, out, nil)
+}
+
+// doWithHeader 发送请求，respHeader 非 nil 时回填响应头
+func (r *ConsulRegistry) doWithHeader(ctx context.Context, client *http.Client, method, path string, in, out interface{}, respHeader http.Header) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.addr+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.config.Token != "" {
+		req.Header.Set("X-Consul-Token", r.config.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: http %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if respHeader != nil {
+		for k, vv := range resp.Header {
+			respHeader[k] = vv
+		}
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package registry
+
+// DiffInstances 对比并更新 known（实例 ID → 序列化结果），返回变更事件。
+// 供只能拉取全量实例列表的驱动（consul、file 等）在 Watch 中生成 Put / Delete 事件
+func DiffInstances(known map[string]string, instances []*ServiceInstance) []WatchEvent {
+	var events []WatchEvent
+	seen := make(map[string]bool, len(instances))
+
+	for _, inst := range instances {
+		seen[inst.ID] = true
+		val, _ := inst.Marshal()
+		if old, ok := known[inst.ID]; ok && old == val {
+			continue
+		}
+		known[inst.ID] = val
+		events = append(events, WatchEvent{Type: EventTypePut, Instance: inst})
+	}
+
+	for id := range known {
+		if !seen[id] {
+			delete(known, id)
+			events = append(events, WatchEvent{
+				Type:     EventTypeDelete,
+				Instance: &ServiceInstance{ID: id},
+			})
+		}
+	}
+	return events
+}
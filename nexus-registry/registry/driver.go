@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DriverFactory 根据配置创建具体的 Registry 实现
+type DriverFactory func(conf *Config) (Registry, error)
+
+var (
+	drivers   = make(map[string]DriverFactory)
+	driversMu sync.RWMutex
+)
+
+// RegisterDriver 注册驱动，通常在实现包的 init 中调用：
+//
+//	func init() { registry.RegisterDriver("etcd", ...) }
+//
+// 重复注册同名驱动会 panic
+func RegisterDriver(name string, factory DriverFactory) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if factory == nil {
+		panic("nexus: RegisterDriver factory is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("nexus: RegisterDriver called twice for driver " + name)
+	}
+	drivers[name] = factory
+}
+
+// Drivers 返回已注册的驱动名（已排序）
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New 按 conf.Driver 创建 Registry，未指定时使用 etcd
+// 对应驱动包需被导入（nexus 顶层包已导入全部内置驱动）
+func New(conf *Config) (Registry, error) {
+	if conf == nil {
+		conf = DefaultConfig()
+	}
+	name := conf.Driver
+	if name == "" {
+		name = DriverEtcd
+	}
+
+	driversMu.RLock()
+	factory, ok := drivers[name]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("nexus: unknown registry driver %q (registered: %v)", name, Drivers())
+	}
+	return factory(conf)
+}
@@ -23,6 +23,12 @@ type EtcdRegistry struct {
 // 编译期检查：确保实现了 Registry 接口
 var _ registry.Registry = (*EtcdRegistry)(nil)
 
+func init() {
+	registry.RegisterDriver(registry.DriverEtcd, func(conf *registry.Config) (registry.Registry, error) {
+		return New(conf)
+	})
+}
+
 func New(conf *registry.Config) (*EtcdRegistry, error) {
 	if conf == nil {
 		conf = registry.DefaultConfig()
@@ -60,6 +66,11 @@ func New(conf *registry.Config) (*EtcdRegistry, error) {
 	}, nil
 }
 
+// Client 返回底层 etcd 客户端，供调用方复用连接（如网关分布式限流）
+func (r *EtcdRegistry) Client() *clientv3.Client {
+	return r.client
+}
+
 func (r *EtcdRegistry) Register(ctx context.Context, instance *registry.ServiceInstance) error {
 	if err := instance.Validate(); err != nil {
 		return err
@@ -0,0 +1,292 @@
+// Package file 基于本地文件系统的 Registry 实现，适用于单机多进程的开发环境。
+//
+// 每个实例一个 JSON 文件：{path}{prefix}/{服务名}/{实例ID}.json
+// 文件 mtime 作为租约时间，注册方按 LeaseTTL/3 周期 touch 续约，
+// 超过 LeaseTTL 未续约的文件视为已过期；Watch 通过定期扫描目录实现。
+package file
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
+)
+
+const (
+	fileExt      = ".json"
+	pollInterval = time.Second
+)
+
+// FileRegistry 文件实现
+type FileRegistry struct {
+	root   string
+	config *registry.Config
+
+	mu         sync.Mutex
+	registered map[string]struct{} // 本客户端注册的实例文件路径
+	stopCh     chan struct{}
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+// 编译期检查：确保实现了 Registry 接口
+var _ registry.Registry = (*FileRegistry)(nil)
+
+func init() {
+	registry.RegisterDriver(registry.DriverFile, func(conf *registry.Config) (registry.Registry, error) {
+		return New(conf)
+	})
+}
+
+func New(conf *registry.Config) (*FileRegistry, error) {
+	if conf == nil {
+		conf = registry.DefaultConfig()
+	}
+	if conf.LeaseTTL <= 0 {
+		conf.LeaseTTL = 15
+	}
+	if conf.Prefix == "" {
+		conf.Prefix = "/nexus/services"
+	}
+
+	root := conf.Path
+	if root == "" {
+		root = filepath.Join(os.TempDir(), "nexus-registry")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("nexus-file: create dir %s: %w", root, err)
+	}
+
+	r := &FileRegistry{
+		root:       root,
+		config:     conf,
+		registered: make(map[string]struct{}),
+		stopCh:     make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.keepAliveLoop()
+	return r, nil
+}
+
+func (r *FileRegistry) ttl() time.Duration {
+	return time.Duration(r.config.LeaseTTL) * time.Second
+}
+
+func (r *FileRegistry) serviceDir(serviceName string) string {
+	return filepath.Join(r.root, filepath.FromSlash(registry.ServicePrefix(r.config.Prefix, serviceName)))
+}
+
+func (r *FileRegistry) instancePath(instance *registry.ServiceInstance) string {
+	return filepath.Join(r.serviceDir(instance.Name), url.PathEscape(instance.ID)+fileExt)
+}
+
+func (r *FileRegistry) Register(ctx context.Context, instance *registry.ServiceInstance) error {
+	if err := instance.Validate(); err != nil {
+		return err
+	}
+
+	val, err := instance.Marshal()
+	if err != nil {
+		return err
+	}
+
+	path := r.instancePath(instance)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("nexus-file: create dir: %w", err)
+	}
+
+	// 先写临时文件再 rename，避免读方看到半截内容
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(val), 0o644); err != nil {
+		return fmt.Errorf("nexus-file: write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("nexus-file: rename %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	r.registered[path] = struct{}{}
+	r.mu.Unlock()
+
+	log.Printf("[nexus-file] registered: %s → %s (%s)", path, instance.Address, instance.Protocol)
+	return nil
+}
+
+// keepAliveLoop 按 TTL/3 周期 touch 本客户端注册的所有实例文件
+func (r *FileRegistry) keepAliveLoop() {
+	defer r.wg.Done()
+
+	interval := r.ttl() / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			r.mu.Lock()
+			for path := range r.registered {
+				if err := os.Chtimes(path, now, now); err != nil {
+					log.Printf("[nexus-file] keepalive %s failed: %v", path, err)
+				}
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+func (r *FileRegistry) Deregister(ctx context.Context, instance *registry.ServiceInstance) error {
+	if instance.ID == "" {
+		instance.ID = instance.Address
+	}
+	path := r.instancePath(instance)
+
+	r.mu.Lock()
+	delete(r.registered, path)
+	r.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("nexus-file: delete %s: %w", path, err)
+	}
+
+	log.Printf("[nexus-file] deregistered: %s", path)
+	return nil
+}
+
+func (r *FileRegistry) Discover(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	dir := r.serviceDir(serviceName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*registry.ServiceInstance{}, nil
+		}
+		return nil, fmt.Errorf("nexus-file: discover %s: %w", serviceName, err)
+	}
+
+	ttl := r.ttl()
+	instances := make([]*registry.ServiceInstance, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		// 租约过期
+		if time.Since(info.ModTime()) > ttl {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		inst, err := registry.UnmarshalInstance(data)
+		if err != nil {
+			log.Printf("[nexus-file] skip bad file %s: %v", path, err)
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+func (r *FileRegistry) DiscoverByProtocol(ctx context.Context, serviceName string, protocol registry.Protocol) ([]*registry.ServiceInstance, error) {
+	all, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*registry.ServiceInstance, 0)
+	for _, inst := range all {
+		if inst.Protocol == protocol {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered, nil
+}
+
+// Watch 定期扫描服务目录，与上一次结果做差集生成 Put / Delete 事件
+func (r *FileRegistry) Watch(ctx context.Context, serviceName string) (<-chan registry.WatchEvent, error) {
+	current, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]string, len(current))
+	registry.DiffInstances(known, current)
+
+	eventCh := make(chan registry.WatchEvent, 64)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer close(eventCh)
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopCh:
+				return
+			case <-ticker.C:
+			}
+
+			instances, err := r.Discover(ctx, serviceName)
+			if err != nil {
+				log.Printf("[nexus-file] watch %s failed: %v", serviceName, err)
+				continue
+			}
+			for _, ev := range registry.DiffInstances(known, instances) {
+				select {
+				case eventCh <- ev:
+				case <-ctx.Done():
+					return
+				case <-r.stopCh:
+					return
+				}
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+func (r *FileRegistry) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	paths := make([]string, 0, len(r.registered))
+	for path := range r.registered {
+		paths = append(paths, path)
+	}
+	r.registered = make(map[string]struct{})
+	r.mu.Unlock()
+
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("[nexus-file] delete %s failed: %v", path, err)
+		}
+	}
+
+	close(r.stopCh)
+	r.wg.Wait()
+	return nil
+}
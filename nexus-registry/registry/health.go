@@ -0,0 +1,271 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthCheckType 主动健康检查方式
+type HealthCheckType string
+
+const (
+	HealthCheckAuto HealthCheckType = ""     // 按实例协议选择：gRPC → grpc，HTTP → http
+	HealthCheckHTTP HealthCheckType = "http" // GET {path}，2xx/3xx 视为健康
+	HealthCheckTCP  HealthCheckType = "tcp"  // 仅建立 TCP 连接
+	HealthCheckGRPC HealthCheckType = "grpc" // grpc.health.v1.Health/Check
+)
+
+// HealthCheckConfig 主动健康检查配置
+type HealthCheckConfig struct {
+	Type               HealthCheckType `toml:"type"                json:"type"                yaml:"type"`
+	Path               string          `toml:"path"                json:"path"                yaml:"path"`         // HTTP 探测路径
+	GRPCService        string          `toml:"grpc_service"        json:"grpc_service"        yaml:"grpc_service"` // grpc.health.v1 服务名，空表示整体
+	IntervalSec        int             `toml:"interval_sec"        json:"interval_sec"        yaml:"interval_sec"`
+	TimeoutMs          int             `toml:"timeout_ms"          json:"timeout_ms"          yaml:"timeout_ms"`
+	HealthyThreshold   int             `toml:"healthy_threshold"   json:"healthy_threshold"   yaml:"healthy_threshold"`   // 连续成功 N 次恢复
+	UnhealthyThreshold int             `toml:"unhealthy_threshold" json:"unhealthy_threshold" yaml:"unhealthy_threshold"` // 连续失败 N 次剔除
+
+	// TLSConfig 返回探测实例使用的 TLS 设置（与业务流量一致），返回 nil 或未设置时使用明文
+	TLSConfig func(serviceName string, inst *ServiceInstance) *tls.Config `toml:"-" json:"-" yaml:"-"`
+}
+
+func (c *HealthCheckConfig) applyDefaults() {
+	if c.Path == "" {
+		c.Path = "/health"
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		c.Path = "/" + c.Path
+	}
+	if c.IntervalSec <= 0 {
+		c.IntervalSec = 10
+	}
+	if c.TimeoutMs <= 0 {
+		c.TimeoutMs = 2000
+	}
+	if c.HealthyThreshold <= 0 {
+		c.HealthyThreshold = 2
+	}
+	if c.UnhealthyThreshold <= 0 {
+		c.UnhealthyThreshold = 3
+	}
+}
+
+// WithHealthCheck 开启主动健康检查：后台定期探测实例，
+// 连续失败达到阈值的实例不再被 Resolve 选中，直到连续成功达到阈值后恢复
+func WithHealthCheck(cfg HealthCheckConfig) ResolverOption {
+	return func(r *Resolver) {
+		cfg.applyDefaults()
+		r.health = newHealthChecker(cfg)
+	}
+}
+
+type instanceHealth struct {
+	healthy   bool
+	successes int // 连续成功次数
+	failures  int // 连续失败次数
+}
+
+// healthChecker 按实例 ID 维护健康状态，新实例默认健康
+type healthChecker struct {
+	cfg        HealthCheckConfig
+	httpClient *http.Client
+
+	mu     sync.RWMutex
+	status map[string]*instanceHealth
+
+	tlsMu      sync.Mutex
+	tlsClients map[*tls.Config]*http.Client // 按 TLS 设置复用 HTTPS 探测连接
+}
+
+func newHealthChecker(cfg HealthCheckConfig) *healthChecker {
+	return &healthChecker{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		},
+		status:     make(map[string]*instanceHealth),
+		tlsClients: make(map[*tls.Config]*http.Client),
+	}
+}
+
+// isHealthy 未探测过的实例视为健康
+func (hc *healthChecker) isHealthy(id string) bool {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+	st, ok := hc.status[id]
+	return !ok || st.healthy
+}
+
+// run 周期探测，instances 每轮获取当前实例快照
+func (hc *healthChecker) run(ctx context.Context, serviceName string, instances func() []*ServiceInstance) {
+	ticker := time.NewTicker(time.Duration(hc.cfg.IntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		hc.checkAll(ctx, serviceName, instances())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (hc *healthChecker) checkAll(ctx context.Context, serviceName string, instances []*ServiceInstance) {
+	var wg sync.WaitGroup
+	results := make([]error, len(instances))
+	for i, inst := range instances {
+		wg.Add(1)
+		go func(i int, inst *ServiceInstance) {
+			defer wg.Done()
+			results[i] = hc.probe(ctx, serviceName, inst)
+		}(i, inst)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	alive := make(map[string]bool, len(instances))
+	for i, inst := range instances {
+		alive[inst.ID] = true
+		st, ok := hc.status[inst.ID]
+		if !ok {
+			st = &instanceHealth{healthy: true}
+			hc.status[inst.ID] = st
+		}
+
+		if err := results[i]; err != nil {
+			st.failures++
+			st.successes = 0
+			if st.healthy && st.failures >= hc.cfg.UnhealthyThreshold {
+				st.healthy = false
+				log.Printf("[nexus] health check: %s %s (%s) marked unhealthy: %v", serviceName, inst.ID, inst.Address, err)
+			}
+		} else {
+			st.successes++
+			st.failures = 0
+			if !st.healthy && st.successes >= hc.cfg.HealthyThreshold {
+				st.healthy = true
+				log.Printf("[nexus] health check: %s %s (%s) recovered", serviceName, inst.ID, inst.Address)
+			}
+		}
+	}
+
+	// 清理已下线实例的状态
+	for id := range hc.status {
+		if !alive[id] {
+			delete(hc.status, id)
+		}
+	}
+}
+
+func (hc *healthChecker) probe(ctx context.Context, serviceName string, inst *ServiceInstance) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(hc.cfg.TimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	typ := hc.cfg.Type
+	if typ == HealthCheckAuto {
+		if inst.Protocol == ProtocolGRPC {
+			typ = HealthCheckGRPC
+		} else {
+			typ = HealthCheckHTTP
+		}
+	}
+
+	var tlsCfg *tls.Config
+	if hc.cfg.TLSConfig != nil {
+		tlsCfg = hc.cfg.TLSConfig(serviceName, inst)
+	}
+
+	switch typ {
+	case HealthCheckHTTP:
+		return hc.probeHTTP(ctx, inst.Address, tlsCfg)
+	case HealthCheckTCP:
+		return probeTCP(ctx, inst.Address)
+	case HealthCheckGRPC:
+		return hc.probeGRPC(ctx, inst.Address, tlsCfg)
+	default:
+		return fmt.Errorf("unsupported health check type: %s", typ)
+	}
+}
+
+func (hc *healthChecker) probeHTTP(ctx context.Context, address string, tlsCfg *tls.Config) error {
+	scheme, client := "http://", hc.httpClient
+	if tlsCfg != nil {
+		scheme, client = "https://", hc.tlsClient(tlsCfg)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+address+hc.cfg.Path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("http status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (hc *healthChecker) tlsClient(tlsCfg *tls.Config) *http.Client {
+	hc.tlsMu.Lock()
+	defer hc.tlsMu.Unlock()
+	c, ok := hc.tlsClients[tlsCfg]
+	if !ok {
+		c = &http.Client{
+			Timeout:   time.Duration(hc.cfg.TimeoutMs) * time.Millisecond,
+			Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		}
+		hc.tlsClients[tlsCfg] = c
+	}
+	return c
+}
+
+func probeTCP(ctx context.Context, address string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (hc *healthChecker) probeGRPC(ctx context.Context, address string, tlsCfg *tls.Config) error {
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: hc.cfg.GRPCService})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("grpc health status %s", resp.GetStatus())
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+// Package memory 提供进程内的 Registry 实现，适用于单元测试和单进程部署。
+//
+// 语义与 etcd 实现保持一致：实例以 Lease 形式注册，由注册方定期续约，
+// 续约停止后超过 LeaseTTL 自动过期并向 Watch 方推送 Delete 事件。
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/krustd/gf-nexus/nexus-registry/registry"
+)
+
+// ==================== Store ====================
+
+// Store 进程内的共享存储，相当于 etcd 服务端
+// 多个 MemoryRegistry 共享同一个 Store 时可以互相发现
+type Store struct {
+	mu       sync.Mutex
+	entries  map[string]*entry // key: prefix/name/id
+	watchers map[*watcher]struct{}
+	leaseSeq int64
+}
+
+type entry struct {
+	instance *registry.ServiceInstance
+	leaseID  int64
+	ttl      time.Duration
+	timer    *time.Timer
+}
+
+// NewStore 创建独立的 Store（测试中每个用例一个，互不干扰）
+func NewStore() *Store {
+	return &Store{
+		entries:  make(map[string]*entry),
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+var defaultStore = NewStore()
+
+// DefaultStore 返回进程级默认 Store
+func DefaultStore() *Store {
+	return defaultStore
+}
+
+func (s *Store) put(key string, inst *registry.ServiceInstance, ttl time.Duration) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.entries[key]; ok {
+		old.timer.Stop()
+	}
+
+	s.leaseSeq++
+	leaseID := s.leaseSeq
+	s.entries[key] = &entry{
+		instance: inst,
+		leaseID:  leaseID,
+		ttl:      ttl,
+		timer:    time.AfterFunc(ttl, func() { s.expire(key, leaseID) }),
+	}
+	s.broadcastLocked(key, registry.WatchEvent{Type: registry.EventTypePut, Instance: cloneInstance(inst)})
+	return leaseID
+}
+
+// renew 续约，lease 已失效时返回 false
+func (s *Store) renew(key string, leaseID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.leaseID != leaseID {
+		return false
+	}
+	e.timer.Reset(e.ttl)
+	return true
+}
+
+func (s *Store) delete(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteLocked(key)
+}
+
+func (s *Store) expire(key string, leaseID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.leaseID != leaseID {
+		return
+	}
+	s.deleteLocked(key)
+	log.Printf("[nexus-memory] lease expired: %s", key)
+}
+
+func (s *Store) deleteLocked(key string) bool {
+	e, ok := s.entries[key]
+	if !ok {
+		return false
+	}
+	e.timer.Stop()
+	delete(s.entries, key)
+	s.broadcastLocked(key, registry.WatchEvent{Type: registry.EventTypeDelete, Instance: cloneInstance(e.instance)})
+	return true
+}
+
+func (s *Store) list(prefix string) []*registry.ServiceInstance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instances := make([]*registry.ServiceInstance, 0)
+	for key, e := range s.entries {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			instances = append(instances, cloneInstance(e.instance))
+		}
+	}
+	return instances
+}
+
+func (s *Store) broadcastLocked(key string, ev registry.WatchEvent) {
+	for w := range s.watchers {
+		if len(key) >= len(w.prefix) && key[:len(w.prefix)] == w.prefix {
+			w.enqueue(ev)
+		}
+	}
+}
+
+func (s *Store) addWatcher(w *watcher) {
+	s.mu.Lock()
+	s.watchers[w] = struct{}{}
+	s.mu.Unlock()
+}
+
+func (s *Store) removeWatcher(w *watcher) {
+	s.mu.Lock()
+	delete(s.watchers, w)
+	s.mu.Unlock()
+}
+
+// ==================== watcher ====================
+
+// watcher 无界队列 + 独立投递 goroutine，保证事件有序且不阻塞 Store
+type watcher struct {
+	prefix string
+
+	mu     sync.Mutex
+	queue  []registry.WatchEvent
+	notify chan struct{}
+}
+
+func (w *watcher) enqueue(ev registry.WatchEvent) {
+	w.mu.Lock()
+	w.queue = append(w.queue, ev)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) drain() []registry.WatchEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	events := w.queue
+	w.queue = nil
+	return events
+}
+
+// ==================== MemoryRegistry ====================
+
+// MemoryRegistry 内存实现
+type MemoryRegistry struct {
+	store  *Store
+	config *registry.Config
+
+	mu         sync.Mutex
+	registered map[string]int64 // key → leaseID
+	closed     bool
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+}
+
+// 编译期检查：确保实现了 Registry 接口
+var _ registry.Registry = (*MemoryRegistry)(nil)
+
+func init() {
+	registry.RegisterDriver(registry.DriverMemory, func(conf *registry.Config) (registry.Registry, error) {
+		return New(conf), nil
+	})
+}
+
+// New 创建使用进程级默认 Store 的内存注册中心
+func New(conf *registry.Config) *MemoryRegistry {
+	return NewWithStore(defaultStore, conf)
+}
+
+// NewWithStore 创建使用指定 Store 的内存注册中心
+func NewWithStore(store *Store, conf *registry.Config) *MemoryRegistry {
+	if conf == nil {
+		conf = registry.DefaultConfig()
+	}
+	if conf.LeaseTTL <= 0 {
+		conf.LeaseTTL = 15
+	}
+	if conf.Prefix == "" {
+		conf.Prefix = "/nexus/services"
+	}
+
+	r := &MemoryRegistry{
+		store:      store,
+		config:     conf,
+		registered: make(map[string]int64),
+		stopCh:     make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.keepAliveLoop()
+	return r
+}
+
+func (r *MemoryRegistry) ttl() time.Duration {
+	return time.Duration(r.config.LeaseTTL) * time.Second
+}
+
+// keepAliveLoop 按 TTL/3 周期续约本客户端注册的所有实例
+func (r *MemoryRegistry) keepAliveLoop() {
+	defer r.wg.Done()
+
+	interval := r.ttl() / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			for key, leaseID := range r.registered {
+				if !r.store.renew(key, leaseID) {
+					delete(r.registered, key)
+					log.Printf("[nexus-memory] keepalive closed: %s", key)
+				}
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+func (r *MemoryRegistry) checkClosed() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return fmt.Errorf("nexus-memory: registry closed")
+	}
+	return nil
+}
+
+func (r *MemoryRegistry) Register(ctx context.Context, instance *registry.ServiceInstance) error {
+	if err := instance.Validate(); err != nil {
+		return err
+	}
+	if err := r.checkClosed(); err != nil {
+		return err
+	}
+
+	key := instance.BuildKey(r.config.Prefix)
+	leaseID := r.store.put(key, cloneInstance(instance), r.ttl())
+
+	r.mu.Lock()
+	r.registered[key] = leaseID
+	r.mu.Unlock()
+
+	log.Printf("[nexus-memory] registered: %s → %s (%s)", key, instance.Address, instance.Protocol)
+	return nil
+}
+
+func (r *MemoryRegistry) Deregister(ctx context.Context, instance *registry.ServiceInstance) error {
+	key := instance.BuildKey(r.config.Prefix)
+
+	r.mu.Lock()
+	delete(r.registered, key)
+	r.mu.Unlock()
+
+	r.store.delete(key)
+	log.Printf("[nexus-memory] deregistered: %s", key)
+	return nil
+}
+
+// StopKeepAlive 停止续约但不删除实例，模拟进程假死：实例将在 LeaseTTL 后过期
+func (r *MemoryRegistry) StopKeepAlive(instance *registry.ServiceInstance) {
+	key := instance.BuildKey(r.config.Prefix)
+	r.mu.Lock()
+	delete(r.registered, key)
+	r.mu.Unlock()
+}
+
+func (r *MemoryRegistry) Discover(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	if err := r.checkClosed(); err != nil {
+		return nil, err
+	}
+	return r.store.list(registry.ServicePrefix(r.config.Prefix, serviceName)), nil
+}
+
+func (r *MemoryRegistry) DiscoverByProtocol(ctx context.Context, serviceName string, protocol registry.Protocol) ([]*registry.ServiceInstance, error) {
+	all, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*registry.ServiceInstance, 0)
+	for _, inst := range all {
+		if inst.Protocol == protocol {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered, nil
+}
+
+func (r *MemoryRegistry) Watch(ctx context.Context, serviceName string) (<-chan registry.WatchEvent, error) {
+	if err := r.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		prefix: registry.ServicePrefix(r.config.Prefix, serviceName),
+		notify: make(chan struct{}, 1),
+	}
+	r.store.addWatcher(w)
+
+	eventCh := make(chan registry.WatchEvent, 64)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer close(eventCh)
+		defer r.store.removeWatcher(w)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopCh:
+				return
+			case <-w.notify:
+				for _, ev := range w.drain() {
+					select {
+					case eventCh <- ev:
+					case <-ctx.Done():
+						return
+					case <-r.stopCh:
+						return
+					}
+				}
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+func (r *MemoryRegistry) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	keys := make([]string, 0, len(r.registered))
+	for key := range r.registered {
+		keys = append(keys, key)
+	}
+	r.registered = make(map[string]int64)
+	r.mu.Unlock()
+
+	for _, key := range keys {
+		r.store.delete(key)
+	}
+
+	close(r.stopCh)
+	r.wg.Wait()
+	return nil
+}
+
+func cloneInstance(inst *registry.ServiceInstance) *registry.ServiceInstance {
+	cp := *inst
+	if inst.Metadata != nil {
+		cp.Metadata = make(map[string]string, len(inst.Metadata))
+		for k, v := range inst.Metadata {
+			cp.Metadata[k] = v
+		}
+	}
+	return &cp
+}
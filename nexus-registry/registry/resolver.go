@@ -11,12 +11,17 @@ type Picker interface {
 	Pick(instances []*ServiceInstance) (*ServiceInstance, error)
 }
 
+// InstanceFilter 在负载均衡前过滤候选实例（如网关的异常实例驱逐），不得修改入参切片；返回值为空时 Resolve 报错
+type InstanceFilter func(serviceName string, instances []*ServiceInstance) []*ServiceInstance
+
 type Resolver struct {
 	registry    Registry // ← 接口
 	serviceName string
 	protocol    Protocol
 	picker      Picker
 	prefix      string
+	health      *healthChecker // 可选，主动健康检查
+	filters     []InstanceFilter
 
 	mu        sync.RWMutex
 	instances []*ServiceInstance
@@ -37,6 +42,11 @@ func WithPrefix(prefix string) ResolverOption {
 	return func(r *Resolver) { r.prefix = prefix }
 }
 
+// WithInstanceFilter 追加实例过滤器，按添加顺序在健康检查之后执行
+func WithInstanceFilter(f InstanceFilter) ResolverOption {
+	return func(r *Resolver) { r.filters = append(r.filters, f) }
+}
+
 // NewResolver 参数是 Registry 接口，不是具体 struct
 func NewResolver(reg Registry, serviceName string, opts ...ResolverOption) (*Resolver, error) {
 	r := &Resolver{
@@ -67,17 +77,59 @@ func NewResolver(reg Registry, serviceName string, opts ...ResolverOption) (*Res
 	}
 	go r.watchLoop(watchCtx, eventCh)
 
+	if r.health != nil {
+		go r.health.run(watchCtx, serviceName, r.GetInstances)
+	}
+
 	log.Printf("[nexus] resolver started: %s (%d instances)", serviceName, len(instances))
 	return r, nil
 }
 
 func (r *Resolver) Resolve() (*ServiceInstance, error) {
+	return r.ResolveExcluding(nil)
+}
+
+// ResolveExcluding 与 Resolve 相同，但优先避开 exclude 中的实例（按 ID），用于重试时换实例；
+// 若排除后无可用实例则退回完整候选集
+func (r *Resolver) ResolveExcluding(exclude map[string]bool) (*ServiceInstance, error) {
 	r.mu.RLock()
 	instances := r.instances
 	r.mu.RUnlock()
 	if len(instances) == 0 {
 		return nil, fmt.Errorf("nexus: no instance for %s", r.serviceName)
 	}
+
+	if r.health != nil {
+		healthy := make([]*ServiceInstance, 0, len(instances))
+		for _, inst := range instances {
+			if r.health.isHealthy(inst.ID) {
+				healthy = append(healthy, inst)
+			}
+		}
+		if len(healthy) == 0 {
+			return nil, fmt.Errorf("nexus: no healthy instance for %s", r.serviceName)
+		}
+		instances = healthy
+	}
+
+	for _, f := range r.filters {
+		instances = f(r.serviceName, instances)
+		if len(instances) == 0 {
+			return nil, fmt.Errorf("nexus: all instances filtered out for %s", r.serviceName)
+		}
+	}
+
+	if len(exclude) > 0 {
+		remaining := make([]*ServiceInstance, 0, len(instances))
+		for _, inst := range instances {
+			if !exclude[inst.ID] {
+				remaining = append(remaining, inst)
+			}
+		}
+		if len(remaining) > 0 {
+			instances = remaining
+		}
+	}
 	return r.picker.Pick(instances)
 }
 